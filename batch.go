@@ -0,0 +1,113 @@
+package tempuscache
+
+import "time"
+
+/*
+Batch operations amortize locking across many keys.
+
+================================================================================
+MOTIVATION
+================================================================================
+
+Handlers that read or write hundreds of keys at once pay one
+Lock()/Unlock() round-trip per key when calling Get/Set in a loop.
+Under contention each round-trip is an opportunity for another
+goroutine to grab the mutex, multiplying latency.
+
+GetMany, SetMany and DeleteMany acquire the exclusive lock exactly
+once for the whole batch and then reuse the single-key internals
+(get, set, delete), so per-key behavior is identical to the
+non-batched API:
+
+- Lazy expiration applies to every key.
+- Hits and misses are counted per key.
+- LRU promotion happens per key, in batch order.
+- Capacity eviction is applied on every insert.
+
+================================================================================
+EVICTION WITHIN A BATCH
+================================================================================
+
+If a batch inserts more new keys than maxEntries allows, the
+regular LRU policy applies: the least recently used entries are
+evicted first. Because batch entries are inserted in order, once
+all older entries are gone the earliest entries of the batch itself
+are evicted, and the last maxEntries entries survive.
+
+================================================================================
+ATOMICITY
+================================================================================
+
+Each batch is applied atomically with respect to other cache
+operations: no other goroutine observes a partially applied batch.
+*/
+
+/*
+Entry describes a single write in a SetMany batch.
+
+TTL follows the same rules as Set:
+- TTL > 0  → expires after the duration
+- TTL == 0 → never expires (existing keys keep their expiration)
+*/
+
+type Entry struct {
+	Key   string
+	Value interface{}
+	TTL   time.Duration
+}
+
+/*
+GetMany retrieves multiple keys under a single lock acquisition.
+
+RETURNS:
+A map containing only the keys that exist and are not expired.
+Missing keys are simply absent from the result.
+*/
+
+func (c *Cache) GetMany(keys []string) map[string]interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result := make(map[string]interface{}, len(keys))
+	for _, key := range keys {
+		if value, found := c.get(key); found {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+/*
+SetMany inserts or updates multiple entries under a single lock
+acquisition. Entries are applied in slice order, so a later entry
+for the same key wins.
+*/
+
+func (c *Cache) SetMany(entries []Entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, e := range entries {
+		c.set(e.Key, e.Value, e.TTL)
+	}
+}
+
+/*
+DeleteMany removes multiple keys under a single lock acquisition.
+
+RETURNS:
+The number of keys that were present and removed.
+*/
+
+func (c *Cache) DeleteMany(keys []string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for _, key := range keys {
+		if c.delete(key) {
+			removed++
+		}
+	}
+	return removed
+}
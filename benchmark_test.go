@@ -2,6 +2,7 @@ package tempuscache
 
 import (
 	"fmt"
+	"runtime"
 	"testing"
 	"time"
 )
@@ -222,3 +223,198 @@ func BenchmarkEviction(b *testing.B) {
 		cache.Set(fmt.Sprintf("key%d", i), i, 0)
 	}
 }
+
+/*
+BenchmarkClockModes compares the precise and coarse clock modes on
+the hot paths.
+
+================================================================================
+OBJECTIVE
+================================================================================
+
+Every Get checks expiration and every Set with a TTL computes an
+expiration timestamp. In the default mode each of these calls
+time.Now(); with WithCoarseClock they read a cached atomic
+timestamp instead.
+
+The preloaded key carries a TTL so the expiration check is not
+short-circuited.
+
+Run with:
+
+    go test -bench=ClockModes -cpu=1,8
+
+to compare the variants side by side.
+*/
+
+func BenchmarkClockModes(b *testing.B) {
+	modes := []struct {
+		name string
+		opts []Option
+	}{
+		{"precise", nil},
+		{"coarse", []Option{WithCoarseClock(time.Millisecond)}},
+	}
+
+	for _, mode := range modes {
+		b.Run(mode.name+"/Get", func(b *testing.B) {
+			cache := New(mode.opts...)
+			defer cache.Close()
+			cache.Set("key", "value", time.Hour)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				cache.Get("key")
+			}
+		})
+
+		b.Run(mode.name+"/Set", func(b *testing.B) {
+			cache := New(mode.opts...)
+			defer cache.Close()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				cache.Set("key", "value", time.Hour)
+			}
+		})
+
+		b.Run(mode.name+"/ParallelGet", func(b *testing.B) {
+			cache := New(mode.opts...)
+			defer cache.Close()
+			cache.Set("key", "value", time.Hour)
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					cache.Get("key")
+				}
+			})
+		})
+	}
+}
+
+/*
+BenchmarkStorageLayouts compares the default list-based storage with
+WithSlabStorage.
+
+================================================================================
+OBJECTIVE
+================================================================================
+
+- SetEvict : Steady-state inserts under eviction pressure
+             (index maintenance plus LRU unlink/relink).
+- GC       : Wall time of a forced full collection with 1M resident
+             entries, i.e. how much the GC has to mark.
+
+Run with:
+
+    go test -bench=StorageLayouts -benchmem
+
+and compare ns/op and allocs/op between the variants.
+*/
+
+func BenchmarkStorageLayouts(b *testing.B) {
+	layouts := []struct {
+		name string
+		opts []Option
+	}{
+		{"list", nil},
+		{"slab", []Option{WithSlabStorage()}},
+	}
+
+	for _, layout := range layouts {
+		b.Run(layout.name+"/SetEvict", func(b *testing.B) {
+			cache := New(append([]Option{WithMaxEntries(1000)}, layout.opts...)...)
+			keys := make([]string, 4096)
+			for i := range keys {
+				keys[i] = fmt.Sprintf("key%d", i)
+			}
+
+			var value interface{} = "value" // boxed once, outside the loop
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				cache.Set(keys[i%len(keys)], value, 0)
+			}
+		})
+
+		b.Run(layout.name+"/GC", func(b *testing.B) {
+			cache := New(layout.opts...)
+			for i := 0; i < 1_000_000; i++ {
+				cache.Set(fmt.Sprintf("key%d", i), i, 0)
+			}
+			runtime.GC()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				runtime.GC()
+			}
+			b.StopTimer()
+			runtime.KeepAlive(cache)
+		})
+	}
+}
+
+/*
+TestSteadyStateAllocations asserts that the hot paths allocate
+nothing once the cache is warm.
+
+================================================================================
+SCENARIO
+================================================================================
+
+The cache runs at capacity with more distinct keys than slots, so
+every Set of an absent key evicts the least recently used entry. The
+evicted item (or slab slot) is recycled for the insert, so neither
+Set nor Get should allocate — for both storage layouts, with and
+without a TTL.
+
+Values are boxed once up front: converting a non-constant value to
+interface{} at the call site would allocate in the caller, not in
+the cache.
+*/
+
+func TestSteadyStateAllocations(t *testing.T) {
+	layouts := map[string][]Option{
+		"list": nil,
+		"slab": {WithSlabStorage()},
+	}
+
+	for name, opts := range layouts {
+		t.Run(name, func(t *testing.T) {
+			cache := New(append([]Option{WithMaxEntries(512)}, opts...)...)
+			keys := make([]string, 2048)
+			for i := range keys {
+				keys[i] = fmt.Sprintf("key%d", i)
+			}
+			var value interface{} = "value"
+
+			i := 0
+			set := func() {
+				cache.Set(keys[i%len(keys)], value, time.Minute)
+				i++
+			}
+			get := func() {
+				cache.Get(keys[i%len(keys)])
+				i++
+			}
+
+			// Warm up: fill to capacity and let the map reach its
+			// steady-state size.
+			for j := 0; j < 10*len(keys); j++ {
+				set()
+			}
+
+			if allocs := testing.AllocsPerRun(10000, set); allocs != 0 {
+				t.Errorf("Set with eviction: %v allocs/op, want 0", allocs)
+			}
+			if allocs := testing.AllocsPerRun(10000, get); allocs != 0 {
+				t.Errorf("Get: %v allocs/op, want 0", allocs)
+			}
+			if stats := cache.Stats(); stats.CapacityEvictions == 0 {
+				t.Fatal("expected the scenario to evict")
+			}
+		})
+	}
+}
@@ -0,0 +1,384 @@
+/*
+Package bytecache is a byte-oriented storage mode for TempusCache,
+designed for millions of small []byte values.
+
+================================================================================
+MOTIVATION
+================================================================================
+
+The core tempuscache.Cache stores every entry as an *Item holding an
+interface{} value behind a *list.Element, all reachable from a
+map[string]*list.Element. Each entry therefore contributes several
+heap pointers, and every GC cycle must scan all of them. With
+millions of entries, mark time dominates.
+
+This package follows the bigcache / freecache design instead:
+
+  - Keys and values are serialized into large ring buffers that are
+    allocated once, up front.
+  - The index is a map[uint64]uint64 from key hash to buffer offset.
+    Neither the map nor the buffers contain pointers, so the GC does
+    not scan them; it only sees a handful of pointers per shard.
+
+================================================================================
+USAGE
+================================================================================
+
+	cache := bytecache.New(
+	    bytecache.WithCapacity(256<<20),  // 256 MiB in total
+	    bytecache.WithShards(64),
+	)
+
+	cache.Set("user:1", payload, time.Minute)
+	value, found := cache.Get("user:1")   // a copy of payload
+
+================================================================================
+SEMANTICS
+================================================================================
+
+  - Get returns a COPY of the value; the ring buffer may be reused as
+    soon as the shard lock is released.
+  - Large values can be compressed transparently (WithCompression,
+    see compression.go).
+  - Eviction is FIFO per shard: when a shard's buffer is full, the
+    oldest entries are dropped to make room, whether or not they were
+    accessed recently. There is no LRU promotion.
+  - Expiration is lazy: expired entries are reported as missing on
+    access and their bytes are reclaimed when the ring wraps over
+    them. No janitor goroutine runs, so there is nothing to Close.
+  - Keys are identified by a 64-bit FNV-1a hash. The stored key is
+    compared on every lookup, so a hash collision is reported as a
+    miss (never as the wrong value); the colliding key written last
+    wins.
+  - Overwrites and deletes leave the old bytes in place as dead space
+    until the ring reclaims them; size WithCapacity for your write
+    rate, not just your live data.
+
+================================================================================
+CONCURRENCY
+================================================================================
+
+Keys are spread over independently locked shards. Reads take the
+shard's read lock; writes take its exclusive lock. Statistics are
+atomic and lock-free, as in the core package.
+*/
+package bytecache
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/Krishna8167/tempuscache/v2"
+)
+
+const (
+	// DefaultShards is the number of shards used without WithShards.
+	DefaultShards = 64
+
+	// DefaultCapacity is the total buffer size used without
+	// WithCapacity.
+	DefaultCapacity = 64 << 20
+)
+
+// ErrEntryTooLarge is returned by Set when the serialized entry does
+// not fit into a single shard, or the key is longer than 65535 bytes.
+var ErrEntryTooLarge = errors.New("bytecache: entry too large")
+
+/*
+Cache is a sharded, byte-oriented cache backed by ring buffers.
+*/
+
+type Cache struct {
+	shards     []*shard
+	mask       uint64
+	clock      tempuscache.Clock
+	stats      counters
+	compressor Compressor
+	threshold  int
+	scratch    sync.Pool
+}
+
+type config struct {
+	shards     int
+	capacity   int
+	clock      tempuscache.Clock
+	compressor Compressor
+	threshold  int
+}
+
+/*
+Option configures a Cache.
+*/
+
+type Option func(*config)
+
+/*
+WithShards sets the number of shards, rounded up to a power of two.
+More shards reduce lock contention but make each ring smaller.
+*/
+
+func WithShards(n int) Option {
+	return func(c *config) {
+		if n > 0 {
+			c.shards = n
+		}
+	}
+}
+
+/*
+WithCapacity sets the total buffer size in bytes, split evenly
+across shards. The largest storable entry is one shard's share.
+*/
+
+func WithCapacity(bytes int) Option {
+	return func(c *config) {
+		if bytes > 0 {
+			c.capacity = bytes
+		}
+	}
+}
+
+/*
+WithClock replaces the time source used for expiration, e.g. with
+the fake clock from the tempuscachetest package.
+*/
+
+func WithClock(clock tempuscache.Clock) Option {
+	return func(c *config) {
+		if clock != nil {
+			c.clock = clock
+		}
+	}
+}
+
+/*
+New creates a Cache and preallocates all ring buffers.
+*/
+
+func New(opts ...Option) *Cache {
+	cfg := config{
+		shards:   DefaultShards,
+		capacity: DefaultCapacity,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	n := 1
+	for n < cfg.shards {
+		n <<= 1
+	}
+
+	per := uint64(cfg.capacity / n)
+	if per < headerSize {
+		per = headerSize
+	}
+
+	c := &Cache{
+		shards:     make([]*shard, n),
+		mask:       uint64(n - 1),
+		clock:      cfg.clock,
+		compressor: cfg.compressor,
+		threshold:  cfg.threshold,
+	}
+	for i := range c.shards {
+		c.shards[i] = newShard(per)
+	}
+	return c
+}
+
+/*
+Set stores a copy of value under key. A non-positive ttl means the
+entry never expires (it can still be evicted).
+
+RETURNS:
+ErrEntryTooLarge if the entry cannot fit into a shard.
+*/
+
+func (c *Cache) Set(key string, value []byte, ttl time.Duration) error {
+	hash := hashKey(key)
+	s := c.shard(hash)
+
+	var flags byte
+	stored := value
+	if c.compressor != nil && len(value) >= c.threshold {
+		scratch, _ := c.scratch.Get().(*[]byte)
+		if scratch == nil {
+			scratch = new([]byte)
+		}
+		defer c.scratch.Put(scratch)
+
+		out, compressed, err := c.compress(value, *scratch)
+		if err != nil {
+			return err
+		}
+		if compressed {
+			*scratch = out
+			stored = out
+			flags |= flagCompressed
+		}
+	}
+
+	size := uint64(headerSize + len(key) + len(stored))
+	if len(key) > math.MaxUint16 || size > uint64(len(s.buf)) {
+		return ErrEntryTooLarge
+	}
+
+	var exp int64
+	if ttl > 0 {
+		exp = c.now() + int64(ttl)
+	}
+
+	s.mu.Lock()
+	off, evicted := s.alloc(size)
+	s.write(off, hash, key, stored, exp, flags)
+	s.mu.Unlock()
+
+	c.stats.sets.Add(1)
+	if flags&flagCompressed != 0 {
+		c.stats.compressed.Add(1)
+		c.stats.bytesSaved.Add(uint64(len(value) - len(stored)))
+	}
+	c.stats.evictions.Add(uint64(evicted))
+	return nil
+}
+
+/*
+Get returns a copy of the value stored under key.
+
+RETURNS:
+- (value, true) -> Key exists and is not expired
+- (nil, false)  -> Key does not exist, is expired or was evicted
+*/
+
+func (c *Cache) Get(key string) ([]byte, bool) {
+	hash := hashKey(key)
+	s := c.shard(hash)
+
+	s.mu.RLock()
+	off, found := s.index[hash]
+	if !found {
+		s.mu.RUnlock()
+		c.stats.misses.Add(1)
+		return nil, false
+	}
+
+	k, v, exp, flags := s.entry(off)
+	if string(k) != key {
+		s.mu.RUnlock()
+		c.stats.collisions.Add(1)
+		c.stats.misses.Add(1)
+		return nil, false
+	}
+
+	if exp != 0 && c.now() > exp {
+		s.mu.RUnlock()
+		c.expire(s, hash, off)
+		c.stats.misses.Add(1)
+		return nil, false
+	}
+
+	var value []byte
+	if flags&flagCompressed != 0 {
+		// Decoding reads straight from the ring, under the read lock.
+		var err error
+		value, err = c.compressor.Decompress(nil, v)
+		if err != nil {
+			s.mu.RUnlock()
+			c.stats.misses.Add(1)
+			return nil, false
+		}
+	} else {
+		value = append([]byte(nil), v...)
+	}
+	s.mu.RUnlock()
+
+	c.stats.hits.Add(1)
+	return value, true
+}
+
+/*
+expire drops the index entry for an expired key, unless it was
+replaced while the read lock was released.
+*/
+
+func (c *Cache) expire(s *shard, hash uint64, off uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cur, found := s.index[hash]; found && cur == off {
+		delete(s.index, hash)
+		c.stats.expirations.Add(1)
+	}
+}
+
+/*
+Delete removes key and reports whether it was present.
+*/
+
+func (c *Cache) Delete(key string) bool {
+	hash := hashKey(key)
+	s := c.shard(hash)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	off, found := s.index[hash]
+	if !found {
+		return false
+	}
+	if k, _, _, _ := s.entry(off); string(k) != key {
+		return false
+	}
+
+	delete(s.index, hash)
+	c.stats.deletes.Add(1)
+	return true
+}
+
+/*
+Len returns the number of indexed entries, including expired entries
+that have not been accessed or reclaimed yet.
+*/
+
+func (c *Cache) Len() int {
+	n := 0
+	for _, s := range c.shards {
+		s.mu.RLock()
+		n += len(s.index)
+		s.mu.RUnlock()
+	}
+	return n
+}
+
+func (c *Cache) shard(hash uint64) *shard {
+	return c.shards[hash&c.mask]
+}
+
+func (c *Cache) now() int64 {
+	if c.clock != nil {
+		return c.clock.Now().UnixNano()
+	}
+	return time.Now().UnixNano()
+}
+
+/*
+hashKey is 64-bit FNV-1a, computed inline so that hashing a string
+key does not allocate.
+*/
+
+func hashKey(key string) uint64 {
+	const (
+		offset64 = 14695981039346656037
+		prime64  = 1099511628211
+	)
+
+	h := uint64(offset64)
+	for i := 0; i < len(key); i++ {
+		h ^= uint64(key[i])
+		h *= prime64
+	}
+	return h
+}
@@ -0,0 +1,351 @@
+package bytecache
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand/v2"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Krishna8167/tempuscache/v2"
+	"github.com/Krishna8167/tempuscache/v2/tempuscachetest"
+)
+
+func TestSetGetDelete(t *testing.T) {
+	cache := New(WithShards(4), WithCapacity(4096))
+
+	if err := cache.Set("a", []byte("alpha"), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	value, found := cache.Get("a")
+	if !found || string(value) != "alpha" {
+		t.Fatalf("expected alpha, got %q", value)
+	}
+
+	// The returned slice is a copy.
+	value[0] = 'X'
+	if value, _ := cache.Get("a"); string(value) != "alpha" {
+		t.Fatalf("expected stored value to be unaffected, got %q", value)
+	}
+
+	cache.Set("a", []byte("beta"), 0)
+	if value, _ := cache.Get("a"); string(value) != "beta" {
+		t.Fatalf("expected overwrite, got %q", value)
+	}
+
+	if !cache.Delete("a") || cache.Delete("a") {
+		t.Fatal("expected exactly one successful delete")
+	}
+	if _, found := cache.Get("a"); found {
+		t.Fatal("expected deleted key to be missing")
+	}
+
+	stats := cache.Stats()
+	if stats.Sets != 2 || stats.Hits != 3 || stats.Misses != 1 || stats.Deletes != 1 || stats.Entries != 0 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+func TestEntryTooLarge(t *testing.T) {
+	cache := New(WithShards(1), WithCapacity(64))
+
+	if err := cache.Set("a", make([]byte, 64), 0); err != ErrEntryTooLarge {
+		t.Fatalf("expected ErrEntryTooLarge, got %v", err)
+	}
+	if err := cache.Set("a", make([]byte, 64-headerSize-1), 0); err != nil {
+		t.Fatalf("expected entry filling the shard to fit, got %v", err)
+	}
+}
+
+/*
+TestFIFOEviction verifies that a full ring drops its oldest entries
+first and reports them as evictions.
+*/
+
+func TestFIFOEviction(t *testing.T) {
+	entry := headerSize + 2 + 8
+	cache := New(WithShards(1), WithCapacity(4*entry))
+
+	for i := 0; i < 6; i++ {
+		cache.Set(fmt.Sprintf("k%d", i), []byte("01234567"), 0)
+	}
+
+	for i := 0; i < 6; i++ {
+		_, found := cache.Get(fmt.Sprintf("k%d", i))
+		if want := i >= 2; found != want {
+			t.Fatalf("k%d: expected found=%v", i, want)
+		}
+	}
+
+	stats := cache.Stats()
+	if stats.Evictions != 2 || stats.Entries != 4 || stats.Bytes != int64(4*entry) {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+func TestExpiration(t *testing.T) {
+	clock := tempuscachetest.NewClock(time.Unix(1_700_000_000, 0))
+	cache := New(WithClock(clock))
+
+	cache.Set("a", []byte("1"), time.Minute)
+	cache.Set("b", []byte("2"), 0)
+
+	clock.Advance(time.Minute + 1)
+
+	if _, found := cache.Get("a"); found {
+		t.Fatal("expected key to be expired")
+	}
+	if _, found := cache.Get("b"); !found {
+		t.Fatal("expected key without TTL to persist")
+	}
+	if stats := cache.Stats(); stats.Expirations != 1 || stats.Entries != 1 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+/*
+TestRingConsistency hammers a tiny ring with random writes of random
+sizes, so it wraps constantly, and checks against a model that every
+key is either evicted or holds exactly its last written value.
+*/
+
+func TestRingConsistency(t *testing.T) {
+	cache := New(WithShards(2), WithCapacity(2048))
+	model := map[string][]byte{}
+	rng := rand.New(rand.NewPCG(7, 7))
+
+	for i := 0; i < 20000; i++ {
+		key := fmt.Sprintf("k%d", rng.IntN(64))
+
+		switch rng.IntN(10) {
+		case 0:
+			cache.Delete(key)
+			delete(model, key)
+		default:
+			value := bytes.Repeat([]byte{byte(i)}, rng.IntN(200))
+			if err := cache.Set(key, value, 0); err != nil {
+				t.Fatal(err)
+			}
+			model[key] = value
+		}
+
+		probe := fmt.Sprintf("k%d", rng.IntN(64))
+		got, found := cache.Get(probe)
+		want, exists := model[probe]
+		if found && (!exists || !bytes.Equal(got, want)) {
+			t.Fatalf("iteration %d: %s returned stale or foreign value", i, probe)
+		}
+	}
+
+	if stats := cache.Stats(); stats.Evictions == 0 {
+		t.Fatal("expected the ring to wrap and evict")
+	}
+}
+
+func TestConcurrentAccess(t *testing.T) {
+	cache := New(WithShards(8), WithCapacity(1<<16))
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 2000; i++ {
+				key := fmt.Sprintf("k%d", (g*i)%300)
+				cache.Set(key, []byte(key), time.Minute)
+				if value, found := cache.Get(key); found && string(value) != key {
+					t.Errorf("expected %s, got %s", key, value)
+					return
+				}
+				if i%7 == 0 {
+					cache.Delete(key)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+/*
+BenchmarkGCPause compares garbage collection cost with the same data
+set stored in the core tempuscache.Cache and in the byte cache.
+
+================================================================================
+METHOD
+================================================================================
+
+Each variant loads gcEntries 64-byte values, then every iteration
+forces a full collection. Reported metrics:
+
+- ns/op          → Wall time of runtime.GC() (dominated by marking)
+- pause-ns/gc    → Average stop-the-world pause from MemStats
+
+The core cache keeps every entry reachable through several pointers
+(map → list element → Item → key, value), so mark time grows with
+the entry count. The byte cache exposes a few pointers per shard.
+
+Run with:
+
+    go test -bench=GCPause -benchtime=20x ./bytecache
+*/
+
+const gcEntries = 1_000_000
+
+func BenchmarkGCPause(b *testing.B) {
+	value := make([]byte, 64)
+
+	b.Run("interface", func(b *testing.B) {
+		cache := tempuscache.New()
+		defer cache.Close()
+		for i := 0; i < gcEntries; i++ {
+			cache.Set(fmt.Sprintf("key%d", i), append([]byte(nil), value...), 0)
+		}
+		benchmarkGC(b)
+		runtime.KeepAlive(cache)
+	})
+
+	b.Run("arena", func(b *testing.B) {
+		cache := New(WithCapacity(gcEntries * 128))
+		for i := 0; i < gcEntries; i++ {
+			cache.Set(fmt.Sprintf("key%d", i), value, 0)
+		}
+		benchmarkGC(b)
+		runtime.KeepAlive(cache)
+	})
+}
+
+func benchmarkGC(b *testing.B) {
+	runtime.GC()
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		runtime.GC()
+	}
+	b.StopTimer()
+
+	runtime.ReadMemStats(&after)
+	if gcs := after.NumGC - before.NumGC; gcs > 0 {
+		b.ReportMetric(float64(after.PauseTotalNs-before.PauseTotalNs)/float64(gcs), "pause-ns/gc")
+	}
+}
+
+/*
+TestCompression verifies that large values are compressed above the
+threshold, returned unchanged by Get, and accounted by stored size.
+*/
+
+func TestCompression(t *testing.T) {
+	cache := New(WithShards(1), WithCapacity(1<<20), WithCompression(256, nil))
+
+	doc := bytes.Repeat([]byte(`{"name":"alice","roles":["admin","dev"]},`), 200)
+	small := []byte("tiny")
+	noise := make([]byte, 4096)
+	rng := rand.New(rand.NewPCG(1, 1))
+	for i := range noise {
+		noise[i] = byte(rng.Uint32())
+	}
+
+	cache.Set("doc", doc, 0)
+	cache.Set("small", small, 0)
+	cache.Set("noise", noise, 0)
+
+	for key, want := range map[string][]byte{"doc": doc, "small": small, "noise": noise} {
+		got, found := cache.Get(key)
+		if !found || !bytes.Equal(got, want) {
+			t.Fatalf("%s: value did not round-trip", key)
+		}
+	}
+
+	stats := cache.Stats()
+	if stats.Compressed != 1 {
+		t.Fatalf("expected only the document to be compressed, got %d", stats.Compressed)
+	}
+	raw := int64(3*headerSize + len("doc") + len(doc) + len("small") + len(small) + len("noise") + len(noise))
+	if stats.Bytes != raw-int64(stats.BytesSaved) || stats.BytesSaved < uint64(len(doc))/2 {
+		t.Fatalf("expected capacity to be charged by compressed size, got %+v (raw %d)", stats, raw)
+	}
+}
+
+/*
+halfCompressor is a toy codec for values made of a repeated half:
+it stores the first half and doubles it back. It verifies that
+custom Compressor implementations are honored.
+*/
+
+type halfCompressor struct{}
+
+func (halfCompressor) Compress(dst, src []byte) ([]byte, error) {
+	return append(dst, src[:len(src)/2]...), nil
+}
+
+func (halfCompressor) Decompress(dst, src []byte) ([]byte, error) {
+	return append(append(dst, src...), src...), nil
+}
+
+func TestCustomCompressor(t *testing.T) {
+	cache := New(WithCompression(4, halfCompressor{}))
+
+	cache.Set("a", []byte("abcabc"), 0)
+	if got, _ := cache.Get("a"); string(got) != "abcabc" {
+		t.Fatalf("expected custom codec round-trip, got %q", got)
+	}
+	if stats := cache.Stats(); stats.Compressed != 1 || stats.BytesSaved != 3 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+func TestFlateCompressorConcurrency(t *testing.T) {
+	cache := New(WithCompression(0, nil))
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				key := fmt.Sprintf("k%d-%d", g, i)
+				value := bytes.Repeat([]byte(key), 50)
+				cache.Set(key, value, 0)
+				if got, found := cache.Get(key); !found || !bytes.Equal(got, value) {
+					t.Errorf("%s: value did not round-trip", key)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func BenchmarkCompression(b *testing.B) {
+	doc := bytes.Repeat([]byte(`{"name":"alice","roles":["admin","dev"]},`), 100)
+
+	for _, mode := range []struct {
+		name string
+		opts []Option
+	}{
+		{"raw", nil},
+		{"flate", []Option{WithCompression(1024, nil)}},
+	} {
+		cache := New(mode.opts...)
+		cache.Set("doc", doc, 0)
+
+		b.Run(mode.name+"/Set", func(b *testing.B) {
+			b.SetBytes(int64(len(doc)))
+			for i := 0; i < b.N; i++ {
+				cache.Set("doc", doc, 0)
+			}
+		})
+		b.Run(mode.name+"/Get", func(b *testing.B) {
+			b.SetBytes(int64(len(doc)))
+			for i := 0; i < b.N; i++ {
+				cache.Get("doc")
+			}
+		})
+	}
+}
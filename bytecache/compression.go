@@ -0,0 +1,156 @@
+package bytecache
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+	"sync"
+)
+
+/*
+Value compression.
+
+================================================================================
+MOTIVATION
+================================================================================
+
+Large, repetitive values (multi-KB JSON documents, HTML fragments)
+often shrink 5–10x when compressed. Since ring capacity is the
+limiting resource, compressing them lets far more entries stay
+resident:
+
+	cache := bytecache.New(
+	    bytecache.WithCompression(1024, nil), // flate, values ≥ 1 KiB
+	)
+
+================================================================================
+SEMANTICS
+================================================================================
+
+- Values of at least threshold bytes are compressed in Set, before the
+  shard lock is taken.
+- The compressed form is only kept if it is actually smaller;
+  incompressible values are stored as-is.
+- A flag in the entry header records the encoding, and Get
+  decompresses transparently. Callers always see the original bytes.
+- Capacity and Stats.Bytes are accounted by STORED (compressed) size.
+  Stats.Compressed and Stats.BytesSaved report how often and how much
+  compression helped.
+
+================================================================================
+PLUGGABLE CODECS
+================================================================================
+
+Any codec (zstd, snappy, lz4, ...) can be plugged in by implementing
+Compressor. The built-in FlateCompressor uses compress/flate from
+the standard library and pools its encoders and decoders, since
+creating them is expensive.
+*/
+
+/*
+Compressor encodes and decodes values.
+
+Both methods append their output to dst and return the extended
+slice, so callers can reuse buffers. Implementations must be safe
+for concurrent use.
+*/
+
+type Compressor interface {
+	Compress(dst, src []byte) ([]byte, error)
+	Decompress(dst, src []byte) ([]byte, error)
+}
+
+/*
+FlateCompressor is a Compressor backed by compress/flate.
+*/
+
+type FlateCompressor struct {
+	level   int
+	writers sync.Pool
+	readers sync.Pool
+}
+
+var _ Compressor = (*FlateCompressor)(nil)
+
+/*
+NewFlateCompressor returns a flate Compressor using level
+(flate.BestSpeed ... flate.BestCompression, or flate.DefaultCompression).
+*/
+
+func NewFlateCompressor(level int) (*FlateCompressor, error) {
+	if _, err := flate.NewWriter(io.Discard, level); err != nil {
+		return nil, err
+	}
+	return &FlateCompressor{level: level}, nil
+}
+
+func (f *FlateCompressor) Compress(dst, src []byte) ([]byte, error) {
+	buf := bytes.NewBuffer(dst)
+
+	w, _ := f.writers.Get().(*flate.Writer)
+	if w == nil {
+		w, _ = flate.NewWriter(buf, f.level)
+	} else {
+		w.Reset(buf)
+	}
+	defer f.writers.Put(w)
+
+	if _, err := w.Write(src); err != nil {
+		return dst, err
+	}
+	if err := w.Close(); err != nil {
+		return dst, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (f *FlateCompressor) Decompress(dst, src []byte) ([]byte, error) {
+	r, _ := f.readers.Get().(io.ReadCloser)
+	if r == nil {
+		r = flate.NewReader(bytes.NewReader(src))
+	} else if err := r.(flate.Resetter).Reset(bytes.NewReader(src), nil); err != nil {
+		return dst, err
+	}
+	defer f.readers.Put(r)
+
+	buf := bytes.NewBuffer(dst)
+	if _, err := buf.ReadFrom(r); err != nil {
+		return dst, err
+	}
+	return buf.Bytes(), nil
+}
+
+/*
+WithCompression compresses values of at least threshold bytes with c.
+A nil c uses a FlateCompressor at flate.BestSpeed.
+*/
+
+func WithCompression(threshold int, c Compressor) Option {
+	return func(cfg *config) {
+		if c == nil {
+			c, _ = NewFlateCompressor(flate.BestSpeed)
+		}
+		cfg.compressor = c
+		cfg.threshold = threshold
+	}
+}
+
+/*
+compress returns the bytes to store for value and whether they are
+compressed. scratch is reused for the compressed output.
+*/
+
+func (c *Cache) compress(value []byte, scratch []byte) ([]byte, bool, error) {
+	if c.compressor == nil || len(value) < c.threshold {
+		return value, false, nil
+	}
+
+	out, err := c.compressor.Compress(scratch[:0], value)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(out) >= len(value) {
+		return value, false, nil
+	}
+	return out, true, nil
+}
@@ -0,0 +1,186 @@
+package bytecache
+
+import (
+	"encoding/binary"
+	"sync"
+)
+
+/*
+Ring buffer shard.
+
+================================================================================
+ENTRY LAYOUT
+================================================================================
+
+Every entry is written contiguously into the shard's buffer:
+
+    +--------+--------+------------+--------+-------+-----+-------+
+    | size   | hash   | expiration | keyLen | flags | key | value |
+    | uint32 | uint64 | int64      | uint16 | uint8 |     |       |
+    +--------+--------+------------+--------+-------+-----+-------+
+    |<----------------- headerSize ---------------->|
+
+size is the total length including the header, so the buffer can be
+walked entry by entry from head to tail without consulting the index.
+flags records how the value is encoded (flagCompressed).
+
+================================================================================
+RING DISCIPLINE
+================================================================================
+
+Entries are appended at tail and reclaimed, oldest first, at head
+(FIFO), exactly like bigcache's BytesQueue:
+
+    not wrapped:  [ free | head ... tail | free ]
+    wrapped:      [ ... tail | free | head ... end | unused ]
+
+An entry never straddles the end of the buffer. When it does not fit
+before the end, the current tail is recorded as end and writing
+restarts at offset 0. When head reaches end it returns to 0 as well.
+
+To make room, entries are evicted from head. An evicted entry is
+only removed from the index if the index still points to it; older
+versions of overwritten or deleted keys are already unreachable and
+are simply skipped ("dead" entries).
+
+Overwrites and deletes therefore never move memory: they only update
+the index, and the dead bytes are reclaimed when head passes them.
+*/
+
+const headerSize = 4 + 8 + 8 + 2 + 1
+
+const flagCompressed = 1 << 0
+
+type shard struct {
+	mu      sync.RWMutex
+	index   map[uint64]uint64 // key hash → entry offset
+	buf     []byte
+	head    uint64 // offset of the oldest entry
+	tail    uint64 // offset where the next entry is written
+	end     uint64 // logical end of data while wrapped
+	wrapped bool
+	entries int // entries in the buffer, including dead ones
+}
+
+func newShard(capacity uint64) *shard {
+	return &shard{
+		index: make(map[uint64]uint64),
+		buf:   make([]byte, capacity),
+	}
+}
+
+/*
+alloc reserves size contiguous bytes and returns their offset,
+evicting the oldest entries as needed. It reports how many LIVE
+entries were evicted.
+
+NOTE:
+The caller must hold the exclusive lock and must have checked that
+size fits the buffer.
+*/
+
+func (s *shard) alloc(size uint64) (uint64, int) {
+	evicted := 0
+	for {
+		if s.entries == 0 {
+			s.head, s.tail, s.end, s.wrapped = 0, 0, 0, false
+		}
+
+		if !s.wrapped {
+			if uint64(len(s.buf))-s.tail >= size {
+				break
+			}
+			s.end = s.tail
+			s.tail = 0
+			s.wrapped = true
+			continue
+		}
+
+		if s.head-s.tail >= size {
+			break
+		}
+		if s.evictHead() {
+			evicted++
+		}
+	}
+
+	off := s.tail
+	s.tail += size
+	s.entries++
+	return off, evicted
+}
+
+/*
+evictHead drops the oldest entry and reports whether it was live.
+*/
+
+func (s *shard) evictHead() bool {
+	off := s.head
+	size := uint64(binary.LittleEndian.Uint32(s.buf[off:]))
+	hash := binary.LittleEndian.Uint64(s.buf[off+4:])
+
+	live := false
+	if cur, found := s.index[hash]; found && cur == off {
+		delete(s.index, hash)
+		live = true
+	}
+
+	s.head += size
+	s.entries--
+	if s.wrapped && s.head == s.end {
+		s.head = 0
+		s.wrapped = false
+	}
+	return live
+}
+
+/*
+write stores an entry at off (obtained from alloc) and indexes it.
+*/
+
+func (s *shard) write(off uint64, hash uint64, key string, value []byte, expiration int64, flags byte) {
+	b := s.buf[off:]
+	size := headerSize + len(key) + len(value)
+
+	binary.LittleEndian.PutUint32(b, uint32(size))
+	binary.LittleEndian.PutUint64(b[4:], hash)
+	binary.LittleEndian.PutUint64(b[12:], uint64(expiration))
+	binary.LittleEndian.PutUint16(b[20:], uint16(len(key)))
+	b[22] = flags
+	copy(b[headerSize:], key)
+	copy(b[headerSize+len(key):size], value)
+
+	s.index[hash] = off
+}
+
+/*
+entry decodes the entry at off. The returned slices alias the
+buffer and are only valid while the lock is held.
+*/
+
+func (s *shard) entry(off uint64) (key []byte, value []byte, expiration int64, flags byte) {
+	b := s.buf[off:]
+	size := int(binary.LittleEndian.Uint32(b))
+	expiration = int64(binary.LittleEndian.Uint64(b[12:]))
+	keyLen := int(binary.LittleEndian.Uint16(b[20:]))
+	flags = b[22]
+
+	key = b[headerSize : headerSize+keyLen]
+	value = b[headerSize+keyLen : size]
+	return key, value, expiration, flags
+}
+
+/*
+used returns the number of buffer bytes occupied by entries,
+live or dead.
+*/
+
+func (s *shard) used() uint64 {
+	if s.entries == 0 {
+		return 0
+	}
+	if s.wrapped {
+		return s.end - s.head + s.tail
+	}
+	return s.tail - s.head
+}
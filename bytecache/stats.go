@@ -0,0 +1,79 @@
+package bytecache
+
+import "sync/atomic"
+
+/*
+Stats is a snapshot of the byte cache statistics.
+
+- Hits        → Successful lookups
+- Misses      → Lookups of missing, expired or colliding keys
+- Sets        → Successful writes
+- Deletes     → Keys removed by Delete
+- Expirations → Expired entries dropped on access
+- Evictions   → Live entries dropped to make room in a ring
+- Collisions  → Lookups that found a different key with the same hash
+- Entries     → Indexed entries (see Cache.Len)
+- Bytes       → Ring buffer bytes in use, including dead entries
+                (compressed values count by their compressed size)
+- Compressed  → Values stored compressed
+- BytesSaved  → Total bytes saved by compression at write time
+
+Like tempuscache.Stats, each field is read atomically but the
+snapshot as a whole is not transactional.
+*/
+
+type Stats struct {
+	Hits        uint64
+	Misses      uint64
+	Sets        uint64
+	Deletes     uint64
+	Expirations uint64
+	Evictions   uint64
+	Collisions  uint64
+	Compressed  uint64
+	BytesSaved  uint64
+
+	Entries int64
+	Bytes   int64
+}
+
+type counters struct {
+	hits        atomic.Uint64
+	misses      atomic.Uint64
+	sets        atomic.Uint64
+	deletes     atomic.Uint64
+	expirations atomic.Uint64
+	evictions   atomic.Uint64
+	collisions  atomic.Uint64
+	compressed  atomic.Uint64
+	bytesSaved  atomic.Uint64
+}
+
+/*
+Stats returns a snapshot of the cache statistics.
+
+The counters are lock-free; Entries and Bytes briefly take each
+shard's read lock.
+*/
+
+func (c *Cache) Stats() Stats {
+	st := Stats{
+		Hits:        c.stats.hits.Load(),
+		Misses:      c.stats.misses.Load(),
+		Sets:        c.stats.sets.Load(),
+		Deletes:     c.stats.deletes.Load(),
+		Expirations: c.stats.expirations.Load(),
+		Evictions:   c.stats.evictions.Load(),
+		Collisions:  c.stats.collisions.Load(),
+		Compressed:  c.stats.compressed.Load(),
+		BytesSaved:  c.stats.bytesSaved.Load(),
+	}
+
+	for _, s := range c.shards {
+		s.mu.RLock()
+		st.Entries += int64(len(s.index))
+		st.Bytes += int64(s.used())
+		s.mu.RUnlock()
+	}
+	return st
+}
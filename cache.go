@@ -1,7 +1,7 @@
 package tempuscache
 
 import (
-	"container/list"
+	"math/rand/v2"
 	"sync"
 	"time"
 )
@@ -21,15 +21,18 @@ ARCHITECTURAL OVERVIEW
 
 TempusCache combines two core data structures:
 
-1. Hash Map (map[string]*list.Element)
+1. Hash Map (map[string]*Item)
    - Provides O(1) key lookup.
-   - Maps keys to their corresponding LRU list elements.
+   - Maps keys to their items.
 
-2. Doubly Linked List (*list.List)
+2. Intrusive Doubly Linked List (Item.prev / Item.next)
    - Maintains LRU ordering.
    - Most recently used items are moved to the front.
    - Oldest items remain at the back for eviction.
 
+Both live behind the internal store interface (see store.go), which
+WithSlabStorage swaps for a pointer-free layout (see slab.go).
+
 ================================================================================
 CONCURRENCY MODEL
 ================================================================================
@@ -60,13 +63,32 @@ TempusCache uses a dual expiration model:
 STRUCTURE FIELDS
 ================================================================================
 
-data       -> Primary storage map (key → *list.Element)
-lru        -> Doubly linked list maintaining LRU ordering
+store      -> Key index plus LRU ordering (see store.go)
 mu         -> Read-write mutex for concurrency control
 maxEntries -> Maximum allowed entries before LRU eviction
 interval   -> Background cleanup interval
 stopChan   -> Graceful shutdown signal for janitor goroutine
-stats      -> Cache performance metrics (hits/misses)
+janitor    -> Handle of the running janitor (nil if none)
+janitorMu  -> Serializes janitor (re)starts; independent of mu
+workers    -> Tracks every background goroutine, awaited by Shutdown
+closeOnce  -> Makes Close/Shutdown idempotent
+closed     -> Set once the cache is closed (guarded by mu and janitorMu)
+stats      -> Atomic performance counters (see stats.go)
+clock      -> Time source for TTLs, expiration and the janitor
+coarse     -> Optional cached timestamp for the hot path (see WithCoarseClock)
+costFn     -> Optional per-entry cost function (see WithCost)
+access     -> Per-key access tracking settings (nil when disabled)
+subs       -> Active event subscriptions (see events.go)
+namespaces -> Shared state of Namespace views, keyed by prefix
+tags       -> Tag index (tag → set of keys) for InvalidateTag
+index      -> Optional ordered key index (see WithKeyIndex)
+name       -> Optional identifier used by metrics exporters
+sweepHook  -> Optional observer invoked after every janitor sweep
+version    -> Write counter used to stamp items for compare-and-swap
+jitter     -> Default TTL jitter fraction (see WithTTLJitter)
+defaultTTL -> TTL applied for DefaultExpiration (see WithDefaultTTL)
+maxTTL     -> Upper bound for every TTL (see WithMaxTTL)
+rng        -> Optional random source for jitter (see WithRandSource)
 
 The design prioritizes:
 - Predictable performance
@@ -75,13 +97,32 @@ The design prioritizes:
 */
 
 type Cache struct {
-	data       map[string]*list.Element
-	lru        *list.List //where each element stores an Item.
+	store      store
 	mu         sync.RWMutex
 	maxEntries int
 	interval   time.Duration
 	stopChan   chan struct{}
-	stats      Stats
+	janitor    *janitor
+	janitorMu  sync.Mutex // serializes janitor restarts
+	workers    sync.WaitGroup
+	closeOnce  sync.Once
+	closed     bool
+	stats      counters
+	clock      Clock
+	coarse     *coarseClock
+	costFn     func(key string, value interface{}) int64
+	access     *accessConfig
+	subs       []*Subscription
+	namespaces map[string]*nsState
+	tags       map[string]map[string]struct{}
+	index      *radixTree
+	name       string
+	sweepHook  func(d time.Duration, removed int)
+	version    uint64 // monotonically increasing write counter (CAS tokens)
+	jitter     float64
+	defaultTTL time.Duration
+	maxTTL     time.Duration
+	rng        *rand.Rand
 	// graceful shutdown pattern, and struct{} uses zero memory.
 }
 
@@ -106,15 +147,16 @@ This pattern ensures forward compatibility and API stability.
 
 func New(opts ...Option) *Cache {
 	c := &Cache{
-		data:     make(map[string]*list.Element),
-		lru:      list.New(),
+		store:    newListStore(),
 		stopChan: make(chan struct{}),
+		clock:    realClock{},
 	}
 
 	for _, opt := range opts {
 		opt(c)
 	}
 
+	c.startCoarseClock()
 	c.startJanitor()
 
 	return c
@@ -126,13 +168,14 @@ Set inserts or updates a key in the cache.
 PARAMETERS:
 - key   : Unique identifier
 - value : Arbitrary data (stored as interface{})
-- ttl   : Time-To-Live duration
+- ttl   : Time-To-Live duration, or one of the sentinels
+          DefaultExpiration / NoExpiration (see ttlpolicy.go)
 
 BEHAVIOR:
 
 1. If key already exists:
    - Update its value.
-   - Recalculate expiration (if ttl > 0).
+   - Recalculate expiration (if a TTL applies; see ttlpolicy.go).
    - Move item to front of LRU list.
 
 2. If key does not exist:
@@ -156,33 +199,152 @@ func (c *Cache) Set(key string, value interface{}, ttl time.Duration) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if elem, found := c.data[key]; found {
-		item := elem.Value.(*Item)
+	c.set(key, value, ttl)
+}
+
+/*
+set contains the shared insert/update logic behind Set and the
+conditional write operations (SetIfAbsent, CompareAndSwap, Update, ...).
+
+Every successful write stamps the item with a fresh version taken from
+the cache-wide counter. Versions are the CAS tokens handed out by
+GetWithVersion.
+
+On a closed cache nothing is stored and nil is returned; public
+methods that need the item check c.closed first.
+
+An existing entry that has already expired is reclaimed first and the
+write proceeds as a fresh insert, so the stale expiration (and any
+other per-entry metadata) is never carried over to the new value.
+
+NOTE:
+The caller must hold the exclusive lock.
+*/
+
+func (c *Cache) set(key string, value interface{}, ttl time.Duration) *Item {
+	return c.setExact(key, value, c.jitterTTL(c.resolveTTL(ttl), c.jitter))
+}
+
+/*
+setExact is set without TTL policy: ttl is applied as given, except
+that a new entry never outlives maxTTL.
+
+- ttl > 0         → Expire after ttl.
+- ttl == 0        → New entry: no expiration. Update: keep the
+                    current expiration.
+- NoExpiration    → Remove any expiration.
+
+Used directly by callers that already resolved the TTL
+(SetWithJitter) or must keep the current expiration regardless of
+WithDefaultTTL (counters).
+*/
+
+func (c *Cache) setExact(key string, value interface{}, ttl time.Duration) *Item {
+	if c.closed {
+		return nil
+	}
+	return c.write(key, value, c.cost(key, value), ttl)
+}
+
+/*
+write is setExact with a precomputed cost, for entries that must not
+be passed to the cost function (negative entries).
+
+NOTE:
+The caller must hold the exclusive lock.
+*/
+
+func (c *Cache) write(key string, value interface{}, cost int64, ttl time.Duration) *Item {
+	if c.closed {
+		return nil
+	}
+
+	c.version++
+	c.stats.sets.Add(1)
+
+	if item, found := c.lookup(key); found {
+		c.stats.replacements.Add(1)
+		c.stats.cost.Add(cost - item.cost)
 		item.value = value
+		item.cost = cost
+		item.version = c.version
+		detachNamespace(item)
+		if item.negative {
+			// A value replacing a negative entry never inherits its TTL;
+			// like a fresh insert, it never outlives maxTTL.
+			item.negative = false
+			item.expiration, item.ttl = 0, 0
+			if ttl <= 0 && c.maxTTL > 0 {
+				ttl = c.maxTTL
+			}
+		}
 		if ttl > 0 {
-			item.expiration = time.Now().Add(ttl).UnixNano()
+			item.expiration = c.now() + int64(ttl)
+			item.ttl = ttl
+		} else if ttl == NoExpiration {
+			item.expiration, item.ttl = 0, 0
 		}
-		c.lru.MoveToFront(elem)
-		return
+		c.store.moveToFront(item)
+		c.publish(EventSet, key, ReasonUpdated)
+		return item
 	}
 
-	if c.maxEntries > 0 && c.lru.Len() >= c.maxEntries {
+	if c.maxEntries > 0 && c.store.len() >= c.maxEntries {
 		c.evictOldest()
 	}
 
+	if ttl <= 0 && c.maxTTL > 0 {
+		ttl = c.maxTTL
+	}
+
 	var exp int64
 	if ttl > 0 {
-		exp = time.Now().Add(ttl).UnixNano()
+		exp = c.now() + int64(ttl)
+	} else {
+		ttl = 0
 	}
 
-	item := &Item{
+	item := c.store.pushFront(Item{
 		key:        key,
 		value:      value,
 		expiration: exp,
+		ttl:        ttl,
+		cost:       cost,
+		version:    c.version,
+	})
+	if c.index != nil {
+		c.index.insert(key)
+	}
+	c.stats.entries.Add(1)
+	c.stats.cost.Add(cost)
+	c.publish(EventSet, key, ReasonInserted)
+	return item
+}
+
+/*
+lookup returns the item for key if it exists and has not expired.
+
+Expired entries are removed on the spot (lazy expiration), exactly like
+Get, but neither LRU ordering nor statistics are touched. This lets the
+conditional operations inspect state without skewing hit/miss metrics.
+
+NOTE:
+The caller must hold the exclusive lock.
+*/
+
+func (c *Cache) lookup(key string) (*Item, bool) {
+	item, found := c.store.get(key)
+	if !found {
+		return nil, false
 	}
 
-	elem := c.lru.PushFront(item)
-	c.data[key] = elem
+	if item.expiredAt(c.now()) {
+		c.removeElement(item, ReasonExpiredLazy)
+		c.stats.lazyExpirations.Add(1)
+		return nil, false
+	}
+
+	return item, true
 }
 
 /*
@@ -228,22 +390,31 @@ func (c *Cache) Get(key string) (interface{}, bool) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	elem, found := c.data[key]
+	return c.get(key)
+}
+
+/*
+get contains the lookup logic shared by Get and GetMany.
+
+NOTE:
+The caller must hold the exclusive lock.
+*/
+
+func (c *Cache) get(key string) (interface{}, bool) {
+	item, found := c.lookup(key)
 	if !found {
-		c.stats.Misses++
+		c.stats.misses.Add(1)
 		return nil, false
 	}
 
-	item := elem.Value.(*Item)
-
-	if item.Expired() {
-		c.removeElement(elem)
-		c.stats.Misses++
+	c.store.moveToFront(item)
+	if item.negative {
+		c.stats.negativeHits.Add(1)
 		return nil, false
 	}
 
-	c.lru.MoveToFront(elem)
-	c.stats.Hits++
+	c.stats.hits.Add(1)
+	c.recordAccess(item)
 	return item.value, true
 }
 
@@ -251,7 +422,7 @@ func (c *Cache) Get(key string) (interface{}, bool) {
 Delete removes a key from the cache.
 
 BEHAVIOR:
-- If key exists → remove from map.
+- If key exists → remove from both the map and the LRU list.
 - If key does not exist → operation is safely ignored.
 
 This operation does not panic on missing keys.
@@ -265,14 +436,94 @@ O(1) average case
 
 func (c *Cache) Delete(key string) {
 	c.mu.Lock()
-	delete(c.data, key)
-	c.mu.Unlock()
+	defer c.mu.Unlock()
+
+	c.delete(key)
 }
 
-func (c *Cache) Stats() Stats {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	return c.stats
+/*
+delete removes key if present and reports whether it existed.
+
+Removal goes through removeElement so the LRU list never keeps an
+orphaned element for a key that is no longer in the map.
+
+NOTE:
+The caller must hold the exclusive lock.
+*/
+
+func (c *Cache) delete(key string) bool {
+	item, found := c.store.get(key)
+	if !found {
+		return false
+	}
+
+	c.removeElement(item, ReasonDeleted)
+	c.stats.deletes.Add(1)
+	return true
+}
+
+/*
+cost evaluates the configured cost function for an entry.
+Without WithCost every entry costs 1, so Stats.Cost equals
+Stats.Entries.
+*/
+
+func (c *Cache) cost(key string, value interface{}) int64 {
+	if c.costFn == nil {
+		return 1
+	}
+	return c.costFn(key, value)
+}
+
+/*
+Name returns the identifier configured via WithName,
+or an empty string if none was set.
+*/
+
+func (c *Cache) Name() string {
+	return c.name
+}
+
+/*
+Clear removes every entry from the cache.
+
+BEHAVIOR:
+- Each entry is removed through removeElement, so subscribers receive
+  an EventDelete with ReasonCleared for every key, and tag, key-index
+  and namespace bookkeeping is released consistently.
+- Removed entries are counted in Stats.Deletes.
+- Entries that had already expired are reclaimed as lazy expirations
+  (ReasonExpiredLazy) instead, keeping the statistics consistent with
+  every other removal path.
+- Configuration (capacity, janitor, subscriptions, ...) is untouched.
+
+RETURNS:
+The number of removed live entries — those Has would have reported.
+Negative entries (SetNotFound) are cleared but not counted.
+
+TIME COMPLEXITY:
+O(n)
+*/
+
+func (c *Cache) Clear() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	removed := 0
+	for item := c.store.back(); item != nil; item = c.store.back() {
+		if item.expiredAt(now) {
+			c.removeElement(item, ReasonExpiredLazy)
+			c.stats.lazyExpirations.Add(1)
+			continue
+		}
+		if !item.negative {
+			removed++
+		}
+		c.removeElement(item, ReasonCleared)
+		c.stats.deletes.Add(1)
+	}
+	return removed
 }
 
 /*
@@ -285,6 +536,7 @@ ALGORITHM:
 - Iterate from the back (oldest entries).
 - Check expiration status.
 - Remove expired elements using removeElement().
+- Return the number of removed entries (reported to the sweep hook).
 
 TIME COMPLEXITY:
 O(n) — full scan of entries.
@@ -297,16 +549,20 @@ Active expiration prevents memory accumulation from expired keys
 that are not accessed frequently enough to trigger lazy deletion.
 */
 
-func (c *Cache) deleteExpired() {
+func (c *Cache) deleteExpired() int {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	for elem := c.lru.Back(); elem != nil; {
-		prev := elem.Prev()
-		item := elem.Value.(*Item)
-		if item.Expired() {
-			c.removeElement(elem)
+	now := c.now()
+	removed := 0
+	c.store.forEachBack(func(item *Item) bool {
+		if item.expiredAt(now) {
+			c.removeElement(item, ReasonExpiredJanitor)
+			removed++
 		}
-		elem = prev
-	}
+		return true
+	})
+
+	c.stats.janitorExpirations.Add(uint64(removed))
+	return removed
 }
@@ -1,6 +1,11 @@
 package tempuscache
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -74,31 +79,6 @@ func TestSetAndGet(t *testing.T) {
 	}
 }
 
-func TestExpiration(t *testing.T) {
-	cache := New()
-
-	cache.Set("a", "b", 1*time.Millisecond)
-	time.Sleep(2 * time.Millisecond)
-
-	_, found := cache.Get("a")
-	if found {
-		t.Fatal("expected key to be expired")
-	}
-}
-
-func TestNoExpiration(t *testing.T) {
-	cache := New()
-
-	cache.Set("a", "b", 0)
-
-	time.Sleep(2 * time.Millisecond)
-
-	val, found := cache.Get("a")
-	if !found || val != "b" {
-		t.Fatal("expected key to persist without TTL")
-	}
-}
-
 func TestDelete(t *testing.T) {
 	cache := New()
 
@@ -202,3 +182,810 @@ func TestStatsTracking(t *testing.T) {
 		t.Fatalf("expected 1 miss, got %d", stats.Misses)
 	}
 }
+
+func TestCompareAndSwap(t *testing.T) {
+	cache := New()
+
+	cache.Set("a", 1, 0)
+
+	_, version, found := cache.GetWithVersion("a")
+	if !found {
+		t.Fatal("expected key to be found")
+	}
+
+	if !cache.CompareAndSwap("a", version, 2, 0) {
+		t.Fatal("expected CAS with current version to succeed")
+	}
+
+	if cache.CompareAndSwap("a", version, 3, 0) {
+		t.Fatal("expected CAS with stale version to fail")
+	}
+
+	if val, _ := cache.Get("a"); val != 2 {
+		t.Fatalf("expected 2, got %v", val)
+	}
+
+	if cache.CompareAndSwap("missing", 0, 1, 0) {
+		t.Fatal("expected CAS on missing key to fail")
+	}
+}
+
+/*
+TestConcurrentUpdate ensures Update is atomic: concurrent increments
+must never be lost.
+*/
+
+func TestConcurrentUpdate(t *testing.T) {
+	cache := New()
+	var wg sync.WaitGroup
+
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cache.Update("counter", 0, func(old interface{}, found bool) (interface{}, bool) {
+				if !found {
+					return 1, true
+				}
+				return old.(int) + 1, true
+			})
+		}()
+	}
+
+	wg.Wait()
+
+	if val, _ := cache.Get("counter"); val != 100 {
+		t.Fatalf("expected 100, got %v", val)
+	}
+
+	val, found := cache.Update("counter", 0, func(old interface{}, found bool) (interface{}, bool) {
+		return nil, false
+	})
+	if !found || val != 100 {
+		t.Fatalf("expected no-op update to return current value, got %v", val)
+	}
+}
+
+/*
+TestCounters verifies Redis-style counter semantics:
+creation with TTL, preserved expiration, type handling and errors.
+*/
+
+func TestCounters(t *testing.T) {
+	cache := New()
+
+	n, err := cache.Incr("hits", time.Minute)
+	if err != nil || n != 1 {
+		t.Fatalf("expected 1, got %d (%v)", n, err)
+	}
+
+	item, _ := cache.store.get("hits")
+	exp := item.expiration
+
+	n, err = cache.IncrBy("hits", 9, time.Hour)
+	if err != nil || n != 10 {
+		t.Fatalf("expected 10, got %d (%v)", n, err)
+	}
+
+	if item, _ := cache.store.get("hits"); item.expiration != exp {
+		t.Fatal("expected later increments to keep the original expiration")
+	}
+
+	if n, _ = cache.Decr("hits", 0); n != 9 {
+		t.Fatalf("expected 9, got %d", n)
+	}
+
+	cache.Set("small", int8(127), 0)
+	if _, err = cache.Incr("small", 0); err != ErrOverflow {
+		t.Fatalf("expected ErrOverflow, got %v", err)
+	}
+
+	cache.Set("text", "abc", 0)
+	if _, err = cache.Incr("text", 0); err != ErrNotInteger {
+		t.Fatalf("expected ErrNotInteger, got %v", err)
+	}
+
+	f, err := cache.IncrByFloat("hits", 0.5, 0)
+	if err != nil || f != 9.5 {
+		t.Fatalf("expected 9.5, got %v (%v)", f, err)
+	}
+
+	if _, err = cache.Incr("hits", 0); err != ErrNotInteger {
+		t.Fatalf("expected float counter to reject integer increment, got %v", err)
+	}
+}
+
+/*
+TestDeleteRemovesFromLRU ensures Delete keeps the map and the LRU list
+in sync, so a later eviction cannot remove a re-inserted key.
+*/
+
+func TestDeleteRemovesFromLRU(t *testing.T) {
+	cache := New(WithMaxEntries(2))
+
+	cache.Set("a", 1, 0)
+	cache.Delete("a")
+
+	if cache.store.len() != 0 {
+		t.Fatalf("expected empty LRU list, got %d elements", cache.store.len())
+	}
+
+	cache.Set("b", 2, 0)
+	cache.Set("a", 1, 0)
+
+	if _, found := cache.Get("a"); !found {
+		t.Fatal("expected re-inserted key to survive")
+	}
+}
+
+func TestBatchOperations(t *testing.T) {
+	cache := New(WithMaxEntries(3))
+
+	cache.Set("old", 0, 0)
+	cache.SetMany([]Entry{
+		{Key: "a", Value: 1},
+		{Key: "b", Value: 2},
+		{Key: "c", Value: 3},
+		{Key: "d", Value: 4},
+	})
+
+	got := cache.GetMany([]string{"old", "a", "b", "c", "d"})
+	if len(got) != 3 || got["b"] != 2 || got["d"] != 4 {
+		t.Fatalf("expected last three batch entries, got %v", got)
+	}
+
+	stats := cache.Stats()
+	if stats.Evictions != 2 || stats.Hits != 3 || stats.Misses != 2 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+
+	if n := cache.DeleteMany([]string{"b", "c", "missing"}); n != 2 {
+		t.Fatalf("expected 2 deletions, got %d", n)
+	}
+
+	if got := cache.GetMany([]string{"b", "c", "d"}); len(got) != 1 {
+		t.Fatalf("expected only d to remain, got %v", got)
+	}
+}
+
+func TestTopKeys(t *testing.T) {
+	cache := New(WithAccessTracking())
+
+	cache.Set("a", 1, 0)
+	cache.Set("b", 2, 0)
+	cache.Set("c", 3, 0)
+
+	for i := 0; i < 3; i++ {
+		cache.Get("b")
+	}
+	cache.Get("a")
+	cache.Peek("c") // not an access
+
+	top := cache.TopKeys(5)
+	if len(top) != 2 || top[0].Key != "b" || top[0].Hits != 3 || top[1].Key != "a" {
+		t.Fatalf("unexpected top keys %+v", top)
+	}
+
+	if top[0].LastAccess.IsZero() {
+		t.Fatal("expected last access time to be recorded")
+	}
+
+	if New().TopKeys(5) != nil {
+		t.Fatal("expected nil when tracking is disabled")
+	}
+}
+
+func TestHotKeyDetection(t *testing.T) {
+	hot := make(chan string, 10)
+
+	cache := New(WithHotKeyDetection(5, time.Minute, func(key string, hits uint64) {
+		hot <- key
+	}))
+
+	cache.Set("hot", 1, 0)
+	cache.Set("cold", 1, 0)
+
+	for i := 0; i < 10; i++ {
+		cache.Get("hot")
+	}
+	cache.Get("cold")
+
+	select {
+	case key := <-hot:
+		if key != "hot" {
+			t.Fatalf("expected hot key, got %q", key)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected hot key callback")
+	}
+
+	// Close waits for every callback goroutine, so any extra callback
+	// has been delivered by the time it returns.
+	cache.Close()
+	if n := len(hot); n != 0 {
+		t.Fatalf("expected a single callback per window, got %d more", n)
+	}
+
+	// Close waits for in-flight callbacks.
+	release := make(chan struct{})
+	finished := false
+	blocking := New(WithHotKeyDetection(1, time.Minute, func(string, uint64) {
+		<-release
+		finished = true
+	}))
+	blocking.Set("k", 1, 0)
+	blocking.Get("k")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := blocking.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected Shutdown to wait for the callback, got %v", err)
+	}
+
+	close(release)
+	blocking.Close()
+	if !finished {
+		t.Fatal("expected Close to return only after the callback finished")
+	}
+}
+
+func TestEventDropPolicy(t *testing.T) {
+	cache := New()
+
+	newest := cache.Subscribe(EventFilter{}, WithEventBuffer(2))
+	oldest := cache.Subscribe(EventFilter{}, WithEventBuffer(2), WithDropPolicy(DropOldest))
+
+	cache.Set("a", 1, 0)
+	cache.Set("b", 1, 0)
+	cache.Set("c", 1, 0)
+
+	if newest.Dropped() != 1 || oldest.Dropped() != 1 {
+		t.Fatalf("expected one drop each, got %d and %d", newest.Dropped(), oldest.Dropped())
+	}
+
+	if ev := <-newest.C; ev.Key != "a" {
+		t.Fatalf("expected DropNewest to keep a, got %s", ev.Key)
+	}
+	if ev := <-oldest.C; ev.Key != "b" {
+		t.Fatalf("expected DropOldest to discard a, got %s", ev.Key)
+	}
+}
+
+/*
+TestNamespace verifies key scoping, default TTL, per-namespace stats
+and O(1) invalidation that leaves other keys untouched.
+*/
+
+func TestNamespace(t *testing.T) {
+	cache := New()
+
+	users := cache.Namespace("user:", time.Minute)
+	orgs := cache.Namespace("org:", 0)
+
+	users.Set("1", "alice", 0)
+	orgs.Set("1", "acme", 0)
+	cache.Set("user:plain", "bob", 0)
+
+	if val, found := cache.Get("user:1"); !found || val != "alice" {
+		t.Fatalf("expected prefixed key in cache, got %v", val)
+	}
+	if ttl, _ := cache.TTL("user:1"); ttl <= 0 {
+		t.Fatal("expected namespace default TTL to apply")
+	}
+	if val, _ := orgs.Get("1"); val != "acme" {
+		t.Fatalf("expected acme, got %v", val)
+	}
+
+	cache.Namespace("user:", 0).Invalidate()
+
+	if _, found := users.Get("1"); found {
+		t.Fatal("expected invalidated namespace key to be gone")
+	}
+	if cache.Has("user:1") {
+		t.Fatal("expected invalidated key to be hidden from the plain API")
+	}
+	if !cache.Has("user:plain") || !cache.Has("org:1") {
+		t.Fatal("expected keys outside the namespace to survive")
+	}
+
+	users.Set("1", "alice2", 0)
+	if val, _ := users.Get("1"); val != "alice2" {
+		t.Fatalf("expected fresh write after invalidation, got %v", val)
+	}
+
+	stats := users.Stats()
+	if stats.Sets != 2 || stats.Hits != 1 || stats.Misses != 1 || stats.Entries != 1 {
+		t.Fatalf("unexpected namespace stats %+v", stats)
+	}
+
+	users.Delete("1")
+	if stats = users.Stats(); stats.Deletes != 1 || stats.Entries != 0 {
+		t.Fatalf("unexpected namespace stats after delete %+v", stats)
+	}
+
+	// A plain write takes the key out of the namespace.
+	users.Set("2", "carol", 0)
+	cache.Set("user:2", "carol2", 0)
+	if n := users.Stats().Entries; n != 0 {
+		t.Fatalf("expected plain overwrite to detach the key, got %d entries", n)
+	}
+	users.Invalidate()
+	if !cache.Has("user:2") {
+		t.Fatal("expected plain-written key to survive namespace invalidation")
+	}
+
+	// Overlapping prefixes: the last writer owns the entry.
+	admins := cache.Namespace("user:admin:", 0)
+	users.Set("admin:1", "root", 0)
+	admins.Set("1", "root2", 0)
+	if u, a := users.Stats().Entries, admins.Stats().Entries; u != 0 || a != 1 {
+		t.Fatalf("expected ownership to move to admins, got users=%d admins=%d", u, a)
+	}
+	admins.Delete("1")
+	if u, a := users.Stats().Entries, admins.Stats().Entries; u != 0 || a != 0 {
+		t.Fatalf("expected no entries after delete, got users=%d admins=%d", u, a)
+	}
+}
+
+/*
+TestTagInvalidation verifies that InvalidateTag removes every tagged
+entry and that the tag index is cleaned up on any removal path.
+*/
+
+func TestTagInvalidation(t *testing.T) {
+	cache := New(WithMaxEntries(4))
+
+	cache.SetWithTags("page:42", "html", 0, "product:42")
+	cache.SetWithTags("list:shoes", "list", 0, "product:42", "product:7")
+	cache.SetWithTags("page:7", "html", 0, "product:7")
+	cache.Set("other", 1, 0)
+
+	if n := cache.InvalidateTag("product:42"); n != 2 {
+		t.Fatalf("expected 2 invalidated entries, got %d", n)
+	}
+	if cache.Has("page:42") || cache.Has("list:shoes") || !cache.Has("page:7") {
+		t.Fatal("unexpected entries after invalidation")
+	}
+
+	if _, found := cache.tags["product:7"]["list:shoes"]; found {
+		t.Fatal("expected removed key to be dropped from other tags")
+	}
+
+	cache.Delete("page:7")
+	if len(cache.tags) != 0 {
+		t.Fatalf("expected empty tag index, got %v", cache.tags)
+	}
+
+	cache.SetWithTags("a", 1, 0, "x")
+	cache.SetWithTags("a", 2, 0, "y")
+	if n := cache.InvalidateTag("x"); n != 0 {
+		t.Fatal("expected retagging to replace previous tags")
+	}
+	if n := cache.InvalidateTag("y"); n != 1 {
+		t.Fatal("expected new tag to be indexed")
+	}
+}
+
+func TestRadixTree(t *testing.T) {
+	var tree radixTree
+
+	keys := []string{"user:10", "user:1", "user:2", "org:1", "user:", "u", ""}
+	for _, k := range keys {
+		tree.insert(k)
+	}
+	tree.insert("user:1") // duplicate
+
+	var got []string
+	tree.walk("", "", func(k string) bool {
+		got = append(got, k)
+		return true
+	})
+	want := "org:1,u,user:,user:1,user:10,user:2"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("expected %s, got %v", want, got)
+	}
+
+	got = got[:0]
+	tree.walk("user:", "user:1", func(k string) bool {
+		got = append(got, k)
+		return true
+	})
+	if strings.Join(got, ",") != "user:10,user:2" {
+		t.Fatalf("unexpected prefix walk %v", got)
+	}
+
+	for _, k := range keys {
+		tree.remove(k)
+	}
+	if len(tree.root.children) != 0 || tree.root.leaf {
+		t.Fatalf("expected empty tree, got %+v", tree.root)
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	cases := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"*", "anything", true},
+		{"user:*", "user:42", true},
+		{"user:*", "org:42", false},
+		{"user:?", "user:4", true},
+		{"user:?", "user:42", false},
+		{"*:[0-9]*", "user:42", true},
+		{"*:[^0-9]*", "user:42", false},
+		{"a\\*b", "a*b", true},
+		{"a\\*b", "axb", false},
+		{"*a*b", "xaxxb", true},
+	}
+
+	for _, tc := range cases {
+		if got := matchGlob(tc.pattern, tc.name); got != tc.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tc.pattern, tc.name, got, tc.want)
+		}
+	}
+}
+
+/*
+TestScan verifies that a full cursor iteration returns every matching
+key exactly once and that each call's work is bounded by count.
+*/
+
+func TestScan(t *testing.T) {
+	cache := New(WithKeyIndex())
+
+	for i := 0; i < 50; i++ {
+		cache.Set(fmt.Sprintf("user:%d", i), i, 0)
+		cache.Set(fmt.Sprintf("org:%d", i), i, 0)
+	}
+
+	seen := map[string]bool{}
+	cursor := ""
+	for calls := 0; ; calls++ {
+		if calls > 100 {
+			t.Fatal("scan did not terminate")
+		}
+		var keys []string
+		keys, cursor = cache.Scan(cursor, "user:*", 7)
+		for _, k := range keys {
+			if seen[k] || !strings.HasPrefix(k, "user:") {
+				t.Fatalf("unexpected key %q", k)
+			}
+			seen[k] = true
+		}
+		if cursor == "" {
+			break
+		}
+	}
+
+	if len(seen) != 50 {
+		t.Fatalf("expected 50 keys, got %d", len(seen))
+	}
+
+	// Dead keys count against count, bounding the work per call.
+	for i := 0; i < 20; i++ {
+		cache.SetNotFound(fmt.Sprintf("dead:%02d", i), 0)
+	}
+	cache.Set("dead:zz", 1, 0)
+	keys, next := cache.Scan("", "dead:*", 5)
+	if len(keys) != 0 || next != "dead:04" {
+		t.Fatalf("expected a 5-key batch of dead keys, got %v %q", keys, next)
+	}
+
+	// Without the index Scan returns nothing rather than copying the
+	// whole key set.
+	plain := New()
+	plain.Set("user:1", 1, 0)
+	if keys, next := plain.Scan("", "*", 10); keys != nil || next != "" {
+		t.Fatalf("expected empty scan without key index, got %v %q", keys, next)
+	}
+}
+
+/*
+TestDeleteByPrefix verifies prefix deletion with and without the key
+index, counting only live entries.
+*/
+
+func TestDeleteByPrefix(t *testing.T) {
+	for _, indexed := range []bool{false, true} {
+		var opts []Option
+		if indexed {
+			opts = append(opts, WithKeyIndex())
+		}
+		cache := New(opts...)
+
+		for i := 0; i < 50; i++ {
+			cache.Set(fmt.Sprintf("user:%d", i), i, 0)
+			cache.Set(fmt.Sprintf("org:%d", i), i, 0)
+		}
+
+		if n := cache.DeleteByPrefix("user:1"); n != 11 {
+			t.Fatalf("indexed=%v: expected 11 deletions, got %d", indexed, n)
+		}
+		if cache.Has("user:12") || !cache.Has("user:2") || !cache.Has("org:1") {
+			t.Fatalf("indexed=%v: unexpected state after DeleteByPrefix", indexed)
+		}
+
+		cache.Set("gone:live", 1, 0)
+		cache.SetNotFound("gone:missing", 0)
+		if n := cache.DeleteByPrefix("gone:"); n != 1 {
+			t.Fatalf("indexed=%v: expected 1 live deletion, got %d", indexed, n)
+		}
+		if _, status := cache.GetWithStatus("gone:missing"); status != StatusMiss {
+			t.Fatalf("indexed=%v: expected negative entry to be removed, got %v", indexed, status)
+		}
+	}
+}
+
+func TestClear(t *testing.T) {
+	cache := New(WithKeyIndex())
+	sub := cache.Subscribe(EventFilter{Types: EventDelete})
+
+	cache.SetWithTags("a", 1, 0, "t")
+	cache.Set("b", 2, 0)
+
+	if n := cache.Clear(); n != 2 {
+		t.Fatalf("expected 2 cleared entries, got %d", n)
+	}
+	sub.Unsubscribe()
+
+	cleared := 0
+	for ev := range sub.C {
+		if ev.Reason == ReasonCleared {
+			cleared++
+		}
+	}
+	if cleared != 2 {
+		t.Fatalf("expected 2 cleared events, got %d", cleared)
+	}
+
+	if cache.Len() != 0 || len(cache.tags) != 0 || len(cache.index.root.children) != 0 {
+		t.Fatal("expected all bookkeeping to be released")
+	}
+	if stats := cache.Stats(); stats.Entries != 0 || stats.Deletes != 2 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+func TestResize(t *testing.T) {
+	cache := New(WithMaxEntries(5))
+
+	for i := 0; i < 5; i++ {
+		cache.Set(fmt.Sprintf("k%d", i), i, 0)
+	}
+	cache.Get("k0") // most recently used
+
+	if n := cache.Resize(2); n != 3 {
+		t.Fatalf("expected 3 evictions, got %d", n)
+	}
+	if !cache.Has("k0") || !cache.Has("k4") || cache.Has("k1") {
+		t.Fatalf("expected LRU entries to be evicted, have %v", cache.Keys())
+	}
+
+	cache.Set("k5", 5, 0)
+	if cache.Len() != 2 {
+		t.Fatalf("expected new limit to apply to inserts, got %d entries", cache.Len())
+	}
+
+	stats := cache.Stats()
+	if stats.ResizeEvictions != 3 || stats.CapacityEvictions != 1 || stats.Evictions != 4 {
+		t.Fatalf("unexpected eviction stats %+v", stats)
+	}
+
+	cache.Resize(0)
+	for i := 0; i < 10; i++ {
+		cache.Set(fmt.Sprintf("n%d", i), i, 0)
+	}
+	if cache.Len() != 12 {
+		t.Fatalf("expected unbounded cache, got %d entries", cache.Len())
+	}
+}
+
+/*
+TestCloseIsIdempotent verifies that Close and Stop can be called
+repeatedly and concurrently, wait for the janitor, and leave the
+cache in a well-defined closed state.
+*/
+
+func TestCloseIsIdempotent(t *testing.T) {
+	cache := New(WithCleanupInterval(time.Millisecond))
+	sub := cache.Subscribe(EventFilter{})
+
+	cache.Set("a", 1, 0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := cache.Close(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	cache.Stop()
+
+	if !cache.Closed() {
+		t.Fatal("expected cache to report closed")
+	}
+
+	for range sub.C {
+	}
+
+	if _, found := cache.Get("a"); found {
+		t.Fatal("expected closed cache to be empty")
+	}
+
+	cache.Set("b", 1, 0)
+	if cache.Has("b") || cache.SetIfAbsent("b", 1, 0) {
+		t.Fatal("expected writes to be ignored after close")
+	}
+
+	if _, err := cache.Incr("n", 0); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+
+	cache.SetCleanupInterval(time.Millisecond)
+	if cache.janitor != nil {
+		t.Fatal("expected janitor not to restart after close")
+	}
+}
+
+func TestShutdownDeadline(t *testing.T) {
+	cache := New(WithCleanupInterval(time.Millisecond))
+
+	// Simulate a background worker that outlives the deadline.
+	release := make(chan struct{})
+	cache.workers.Add(1)
+	go func() {
+		defer cache.workers.Done()
+		<-release
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := cache.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline error, got %v", err)
+	}
+	if !cache.Closed() {
+		t.Fatal("expected cache to be closed despite the deadline")
+	}
+
+	close(release)
+	if err := cache.Close(); err != nil {
+		t.Fatalf("expected clean close once workers exit, got %v", err)
+	}
+}
+
+/*
+TestNegativeCaching verifies that known-missing entries are reported
+distinctly by GetWithStatus, hidden from every value-reading API,
+replaced by a later Set and counted in their own statistics.
+*/
+
+func TestNegativeCaching(t *testing.T) {
+	cache := New()
+
+	if _, status := cache.GetWithStatus("user:1"); status != StatusMiss {
+		t.Fatalf("expected miss, got %v", status)
+	}
+
+	cache.SetNotFound("user:1", time.Minute)
+
+	if _, status := cache.GetWithStatus("user:1"); status != StatusNotFound {
+		t.Fatalf("expected not_found, got %v", status)
+	}
+	if _, found := cache.Get("user:1"); found {
+		t.Fatal("expected Get to report negative entry as not found")
+	}
+	if cache.Has("user:1") || cache.Len() != 0 || len(cache.Keys()) != 0 {
+		t.Fatal("expected negative entry to be hidden from inspection")
+	}
+	if _, found := cache.TTL("user:1"); found {
+		t.Fatal("expected TTL to treat negative entry as missing")
+	}
+	if !cache.SetIfAbsent("user:2", 1, 0) || cache.SetIfPresent("user:1", 1, 0) {
+		t.Fatal("expected conditional writes to treat negative entry as missing")
+	}
+
+	if _, _, found := cache.GetWithVersion("user:1"); found {
+		t.Fatal("expected GetWithVersion to report negative entry as not found")
+	}
+
+	stats := cache.Stats()
+	if stats.NegativeSets != 1 || stats.NegativeHits != 3 || stats.Misses != 1 || stats.Hits != 0 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+
+	cache.Set("user:1", "alice", 0)
+	if value, status := cache.GetWithStatus("user:1"); status != StatusHit || value != "alice" {
+		t.Fatalf("expected hit after Set, got %v %v", value, status)
+	}
+	if ttl, _ := cache.TTL("user:1"); ttl != 0 {
+		t.Fatalf("expected value not to inherit negative TTL, got %v", ttl)
+	}
+
+	if n, err := cache.Incr("user:3", 0); err != nil || n != 1 {
+		t.Fatalf("unexpected Incr result %d %v", n, err)
+	}
+	cache.SetNotFound("user:3", 0)
+	if n, err := cache.Incr("user:3", 0); err != nil || n != 1 {
+		t.Fatalf("expected Incr to restart a negative counter, got %d %v", n, err)
+	}
+
+	// Negative entries are never passed to the cost function.
+	costly := New(WithCost(func(key string, value interface{}) int64 {
+		return int64(len(value.(string)))
+	}))
+	costly.Set("a", "abcd", 0)
+	costly.SetNotFound("b", time.Minute)
+	costly.SetNotFound("a", time.Minute)
+	if cost := costly.Stats().Cost; cost != 2*negativeCost {
+		t.Fatalf("expected fixed cost for negative entries, got %d", cost)
+	}
+	costly.Set("b", "xyz", 0)
+	if cost := costly.Stats().Cost; cost != negativeCost+3 {
+		t.Fatalf("expected value cost after replacing a negative entry, got %d", cost)
+	}
+}
+
+/*
+TestStorageLayouts drives a list-backed and a slab-backed cache with
+the same random operation sequence and checks that they stay
+indistinguishable: same LRU order, same values, same statistics.
+*/
+
+func TestStorageLayouts(t *testing.T) {
+	listCache := New(WithMaxEntries(50), WithKeyIndex())
+	slabCache := New(WithMaxEntries(50), WithKeyIndex(), WithSlabStorage())
+	rng := rand.New(rand.NewPCG(3, 4))
+
+	for i := 0; i < 20000; i++ {
+		key := fmt.Sprintf("k%d", rng.IntN(80))
+
+		for _, cache := range []*Cache{listCache, slabCache} {
+			switch op := i % 7; op {
+			case 0, 1, 2:
+				cache.Set(key, i, 0)
+			case 3:
+				cache.Get(key)
+			case 4:
+				cache.Delete(key)
+			case 5:
+				cache.Touch(key)
+			case 6:
+				if i%700 == 6 {
+					cache.Resize(30 + i%40)
+				}
+			}
+		}
+
+		if i%500 == 0 {
+			if a, b := fmt.Sprint(listCache.Keys()), fmt.Sprint(slabCache.Keys()); a != b {
+				t.Fatalf("iteration %d: LRU order diverged:\n%s\n%s", i, a, b)
+			}
+		}
+	}
+
+	for _, key := range listCache.Keys() {
+		a, _ := listCache.Peek(key)
+		b, _ := slabCache.Peek(key)
+		if a != b {
+			t.Fatalf("%s: %v != %v", key, a, b)
+		}
+	}
+	if a, b := listCache.Stats(), slabCache.Stats(); a != b {
+		t.Fatalf("stats diverged:\n%+v\n%+v", a, b)
+	}
+
+	slabCache.Clear()
+	if slabCache.Len() != 0 || slabCache.store.len() != 0 {
+		t.Fatal("expected empty slabCache store after Clear")
+	}
+	slabCache.Set("again", 1, 0)
+	if v, found := slabCache.Get("again"); !found || v != 1 {
+		t.Fatal("expected slabCache store to be reusable after Clear")
+	}
+}
@@ -0,0 +1,168 @@
+package tempuscache
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+/*
+Clock abstracts the time source used by the cache.
+
+================================================================================
+MOTIVATION
+================================================================================
+
+Expiration is inherently time-dependent. With time.Now() and
+time.NewTicker hard-wired, tests must sleep past TTLs and janitor
+intervals, which makes them slow and flaky on loaded machines.
+
+Every time-dependent code path — TTL computation, expiration checks,
+access tracking, event timestamps and the janitor ticker — goes
+through the Clock configured with WithClock. Production code uses
+the real clock; tests can inject the fake clock from the
+tempuscachetest package and advance time explicitly:
+
+    clock := tempuscachetest.NewClock(time.Now())
+    cache := tempuscache.New(tempuscache.WithClock(clock))
+
+    cache.Set("a", 1, time.Minute)
+    clock.Advance(2 * time.Minute)   // "a" is now expired
+
+================================================================================
+CONTRACT
+================================================================================
+
+- Now must be safe for concurrent use and should be monotonic
+  non-decreasing.
+- NewTicker must deliver ticks on C() roughly every d until Stop.
+*/
+
+type Clock interface {
+	Now() time.Time
+	NewTicker(d time.Duration) Ticker
+}
+
+/*
+Ticker is the subset of *time.Ticker used by the janitor.
+*/
+
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+/*
+realClock is the default Clock backed by the time package.
+*/
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+type realTicker struct {
+	t *time.Ticker
+}
+
+func (r realTicker) C() <-chan time.Time {
+	return r.t.C
+}
+
+func (r realTicker) Stop() {
+	r.t.Stop()
+}
+
+/*
+now returns the current time of the configured clock in Unix
+nanoseconds, the representation used for Item.expiration.
+
+In coarse mode it returns the cached timestamp instead.
+*/
+
+func (c *Cache) now() int64 {
+	if c.coarse != nil {
+		return c.coarse.nanos.Load()
+	}
+	return c.clock.Now().UnixNano()
+}
+
+/*
+Coarse clock mode.
+
+================================================================================
+MOTIVATION
+================================================================================
+
+Every Get checks expiration, and every Set computes an expiration
+timestamp. Both read the clock, and under read-heavy load the
+time.Now() call is a measurable part of the hot path.
+
+With WithCoarseClock(resolution), a background goroutine refreshes
+an atomic timestamp every resolution; expiration checks and TTL
+computation read that timestamp with a single atomic load.
+
+================================================================================
+ACCURACY
+================================================================================
+
+The cached timestamp lags real time by at most one resolution, so
+an entry may outlive its TTL by up to one resolution. Pick a
+resolution that is small compared to your shortest TTL (1ms is
+usually plenty).
+
+Event timestamps, sweep durations and ExpireAt deadlines still read
+the precise clock.
+
+================================================================================
+LIFECYCLE
+================================================================================
+
+The refresher is driven by the configured Clock's ticker (so the
+fake clock in tempuscachetest controls it too), is tracked in
+c.workers and exits on Close. After Close the timestamp stops
+advancing, which is harmless because the cache is empty.
+*/
+
+/*
+coarseClock holds the cached timestamp and its refresh interval.
+*/
+
+type coarseClock struct {
+	resolution time.Duration
+	nanos      atomic.Int64
+}
+
+/*
+startCoarseClock seeds the cached timestamp and launches the
+refresher goroutine. It is a no-op unless WithCoarseClock was used.
+*/
+
+func (c *Cache) startCoarseClock() {
+	if c.coarse == nil {
+		return
+	}
+
+	cc := c.coarse
+	cc.nanos.Store(c.clock.Now().UnixNano())
+
+	ticker := c.clock.NewTicker(cc.resolution)
+	c.workers.Add(1)
+
+	go func() {
+		defer c.workers.Done()
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C():
+				cc.nanos.Store(c.clock.Now().UnixNano())
+			case <-c.stopChan:
+				return
+			}
+		}
+	}()
+}
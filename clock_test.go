@@ -0,0 +1,746 @@
+package tempuscache_test
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Krishna8167/tempuscache/v2"
+	"github.com/Krishna8167/tempuscache/v2/tempuscachetest"
+)
+
+/*
+clock_test.go holds every time-dependent test.
+
+================================================================================
+DETERMINISTIC TIME
+================================================================================
+
+All tests here drive the cache through the fake clock from the
+tempuscachetest package instead of sleeping:
+
+- TTLs expire the instant clock.Advance crosses them.
+- Janitor ticks fire only when the test advances time.
+
+They therefore run in microseconds and cannot flake on a loaded
+machine. They live in the external test package because
+tempuscachetest itself imports tempuscache.
+*/
+
+func newFakeCache(opts ...tempuscache.Option) (*tempuscache.Cache, *tempuscachetest.Clock) {
+	clock := tempuscachetest.NewClock(time.Unix(1_700_000_000, 0))
+	return tempuscache.New(append([]tempuscache.Option{tempuscache.WithClock(clock)}, opts...)...), clock
+}
+
+func TestExpiration(t *testing.T) {
+	cache, clock := newFakeCache()
+
+	cache.Set("a", "b", 1*time.Millisecond)
+	clock.Advance(2 * time.Millisecond)
+
+	_, found := cache.Get("a")
+	if found {
+		t.Fatal("expected key to be expired")
+	}
+}
+
+func TestNoExpiration(t *testing.T) {
+	cache, clock := newFakeCache()
+
+	cache.Set("a", "b", 0)
+
+	clock.Advance(24 * time.Hour)
+
+	val, found := cache.Get("a")
+	if !found || val != "b" {
+		t.Fatal("expected key to persist without TTL")
+	}
+}
+
+/*
+TestConditionalWrites verifies SetIfAbsent / SetIfPresent semantics,
+including treating expired entries as absent.
+*/
+
+func TestConditionalWrites(t *testing.T) {
+	cache, clock := newFakeCache()
+
+	if cache.SetIfPresent("a", 1, 0) {
+		t.Fatal("expected SetIfPresent to fail on missing key")
+	}
+
+	if !cache.SetIfAbsent("a", 1, 0) {
+		t.Fatal("expected SetIfAbsent to store missing key")
+	}
+
+	if cache.SetIfAbsent("a", 2, 0) {
+		t.Fatal("expected SetIfAbsent to fail on existing key")
+	}
+
+	if !cache.SetIfPresent("a", 3, 0) {
+		t.Fatal("expected SetIfPresent to replace existing key")
+	}
+
+	if val, _ := cache.Get("a"); val != 3 {
+		t.Fatalf("expected 3, got %v", val)
+	}
+
+	cache.Set("b", 1, time.Millisecond)
+	clock.Advance(2 * time.Millisecond)
+
+	if !cache.SetIfAbsent("b", 2, 0) {
+		t.Fatal("expected expired key to be treated as absent")
+	}
+}
+
+func TestTTLIntrospection(t *testing.T) {
+	cache, clock := newFakeCache()
+
+	if _, found := cache.TTL("missing"); found {
+		t.Fatal("expected missing key to report no TTL")
+	}
+
+	cache.Set("forever", 1, 0)
+	if ttl, found := cache.TTL("forever"); !found || ttl != 0 {
+		t.Fatalf("expected persistent key to report 0, got %v", ttl)
+	}
+
+	cache.Set("a", 1, time.Minute)
+	clock.Advance(20 * time.Second)
+	if ttl, found := cache.TTL("a"); !found || ttl != 40*time.Second {
+		t.Fatalf("expected exactly 40s remaining, got %v", ttl)
+	}
+
+	cache.Set("b", 1, time.Millisecond)
+	clock.Advance(2 * time.Millisecond)
+	if _, found := cache.TTL("b"); found {
+		t.Fatal("expected expired key to report no TTL")
+	}
+}
+
+/*
+TestExpireAndPersist verifies that Expire shortens lifetimes so lazy
+expiration kicks in, that Persist rescues a key from expiring, and
+that expired keys cannot be revived.
+*/
+
+func TestExpireAndPersist(t *testing.T) {
+	cache, clock := newFakeCache()
+
+	cache.Set("a", 1, 0)
+	if !cache.Expire("a", time.Millisecond) {
+		t.Fatal("expected Expire to find key")
+	}
+	clock.Advance(2 * time.Millisecond)
+	if _, found := cache.Get("a"); found {
+		t.Fatal("expected key to expire after Expire")
+	}
+	if cache.Persist("a") {
+		t.Fatal("expected Persist to fail on expired key")
+	}
+
+	cache.Set("b", 1, time.Millisecond)
+	if !cache.Persist("b") {
+		t.Fatal("expected Persist to find key")
+	}
+	clock.Advance(2 * time.Millisecond)
+	if val, found := cache.Get("b"); !found || val != 1 {
+		t.Fatal("expected persisted key to survive")
+	}
+
+	cache.Set("c", 1, 0)
+	if !cache.ExpireAt("c", clock.Now().Add(-time.Second)) {
+		t.Fatal("expected ExpireAt to find key")
+	}
+	if _, found := cache.Get("c"); found {
+		t.Fatal("expected past deadline to delete key")
+	}
+}
+
+func TestTouch(t *testing.T) {
+	cache, clock := newFakeCache()
+
+	cache.Set("a", 1, 20*time.Millisecond)
+	clock.Advance(15 * time.Millisecond)
+
+	if !cache.Touch("a") {
+		t.Fatal("expected Touch to find key")
+	}
+
+	clock.Advance(15 * time.Millisecond)
+	if _, found := cache.Get("a"); !found {
+		t.Fatal("expected touched key to still be alive")
+	}
+
+	cache.Set("b", 1, time.Millisecond)
+	clock.Advance(2 * time.Millisecond)
+	if cache.Touch("b") {
+		t.Fatal("expected Touch to fail on expired key")
+	}
+}
+
+/*
+TestPeekHasNoSideEffects verifies that inspection methods neither
+promote entries, delete expired ones, nor touch statistics.
+*/
+
+func TestPeekHasNoSideEffects(t *testing.T) {
+	cache, clock := newFakeCache(tempuscache.WithMaxEntries(2))
+
+	cache.Set("a", 1, 0)
+	cache.Set("b", 2, 0)
+
+	if val, found := cache.Peek("a"); !found || val != 1 {
+		t.Fatalf("expected to peek 1, got %v", val)
+	}
+	if !cache.Has("b") || cache.Has("missing") {
+		t.Fatal("unexpected Has result")
+	}
+
+	// Peek must not promote "a", so it is still the eviction candidate.
+	cache.Set("c", 3, 0)
+	if cache.Has("a") {
+		t.Fatal("expected peeked key to be evicted as least recently used")
+	}
+
+	cache.Set("d", 4, time.Millisecond)
+	clock.Advance(2 * time.Millisecond)
+	if cache.Has("d") {
+		t.Fatal("expected expired key to be reported absent")
+	}
+
+	stats := cache.Stats()
+	if stats.Entries != 2 {
+		t.Fatalf("expected Has to leave expired key for cleanup, got %d entries", stats.Entries)
+	}
+	if stats.Hits != 0 || stats.Misses != 0 {
+		t.Fatalf("expected untouched stats, got %+v", stats)
+	}
+}
+
+func TestIteration(t *testing.T) {
+	cache, clock := newFakeCache()
+
+	cache.Set("a", 1, 0)
+	cache.Set("b", 2, 0)
+	cache.Set("expired", 3, time.Millisecond)
+	clock.Advance(2 * time.Millisecond)
+
+	if n := cache.Len(); n != 2 {
+		t.Fatalf("expected 2 live entries, got %d", n)
+	}
+
+	keys := cache.Keys()
+	if len(keys) != 2 || keys[0] != "b" || keys[1] != "a" {
+		t.Fatalf("expected [b a], got %v", keys)
+	}
+
+	sum := 0
+	for _, value := range cache.All() {
+		sum += value.(int)
+	}
+	if sum != 3 {
+		t.Fatalf("expected sum 3, got %d", sum)
+	}
+
+	visited := 0
+	cache.Range(func(key string, value interface{}) bool {
+		visited++
+		return false
+	})
+	if visited != 1 {
+		t.Fatalf("expected Range to stop early, visited %d", visited)
+	}
+
+	// Callbacks run outside the lock, so calling back into the cache
+	// (even while a writer is waiting) must not deadlock.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cache.Range(func(key string, value interface{}) bool {
+			writer := make(chan struct{})
+			go func() {
+				cache.Set("w", 0, 0)
+				close(writer)
+			}()
+			cache.Peek(key)
+			cache.Set(key+"-copy", value, 0)
+			<-writer
+			return true
+		})
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Range callback calling back into the cache deadlocked")
+	}
+	if !cache.Has("a-copy") || !cache.Has("b-copy") {
+		t.Fatal("expected writes from Range callbacks to be applied")
+	}
+}
+
+/*
+TestRicherStats verifies the extended counters, gauges, derived
+hit ratio and atomic reset.
+*/
+
+func TestRicherStats(t *testing.T) {
+	cache, clock := newFakeCache(
+		tempuscache.WithMaxEntries(2),
+		tempuscache.WithCost(func(key string, value interface{}) int64 {
+			return int64(len(value.(string)))
+		}),
+	)
+
+	cache.Set("a", "xx", 0)
+	cache.Set("a", "xxx", 0) // replacement
+	cache.Set("b", "y", time.Millisecond)
+	cache.Set("c", "zzzz", 0) // evicts "a"
+	clock.Advance(2 * time.Millisecond)
+
+	cache.Get("b") // lazy expiration + miss
+	cache.Get("c") // hit
+	cache.Delete("c")
+
+	stats := cache.Stats()
+	if stats.Sets != 4 || stats.Replacements != 1 || stats.Deletes != 1 {
+		t.Fatalf("unexpected write counters %+v", stats)
+	}
+	if stats.Evictions != 1 || stats.CapacityEvictions != 1 {
+		t.Fatalf("unexpected eviction counters %+v", stats)
+	}
+	if stats.Expirations != 1 || stats.LazyExpirations != 1 {
+		t.Fatalf("unexpected expiration counters %+v", stats)
+	}
+	if stats.Entries != 0 || stats.Cost != 0 {
+		t.Fatalf("expected empty gauges, got %+v", stats)
+	}
+	if stats.HitRatio() != 0.5 {
+		t.Fatalf("expected hit ratio 0.5, got %v", stats.HitRatio())
+	}
+
+	cache.Set("d", "abc", 0)
+
+	prev := cache.ResetStats()
+	if prev.Sets != 5 || prev.Hits != 1 {
+		t.Fatalf("expected previous snapshot, got %+v", prev)
+	}
+
+	stats = cache.Stats()
+	if stats.Sets != 0 || stats.Hits != 0 || stats.Entries != 1 || stats.Cost != 3 {
+		t.Fatalf("expected reset counters and intact gauges, got %+v", stats)
+	}
+}
+
+/*
+TestEventStream verifies that every mutation path publishes an event
+with the correct type, reason and clock timestamp, and that filters
+are honored.
+*/
+
+func TestEventStream(t *testing.T) {
+	cache, clock := newFakeCache(tempuscache.WithMaxEntries(1))
+
+	all := cache.Subscribe(tempuscache.EventFilter{})
+	removals := cache.Subscribe(tempuscache.EventFilter{
+		Types:  tempuscache.EventExpire | tempuscache.EventEvict,
+		Prefix: "user:",
+	})
+
+	cache.Set("user:1", 1, time.Millisecond)
+	cache.Set("user:1", 2, time.Millisecond)
+	clock.Advance(2 * time.Millisecond)
+	cache.Get("user:1")
+	cache.Set("user:2", 1, 0)
+	cache.Set("other", 1, 0)
+	cache.Delete("other")
+
+	all.Unsubscribe()
+	removals.Unsubscribe()
+	all.Unsubscribe() // idempotent
+
+	var got []tempuscache.Event
+	for ev := range all.C {
+		got = append(got, ev)
+	}
+
+	want := []struct {
+		t tempuscache.EventType
+		k string
+		r tempuscache.Reason
+	}{
+		{tempuscache.EventSet, "user:1", tempuscache.ReasonInserted},
+		{tempuscache.EventSet, "user:1", tempuscache.ReasonUpdated},
+		{tempuscache.EventExpire, "user:1", tempuscache.ReasonExpiredLazy},
+		{tempuscache.EventSet, "user:2", tempuscache.ReasonInserted},
+		{tempuscache.EventEvict, "user:2", tempuscache.ReasonCapacity},
+		{tempuscache.EventSet, "other", tempuscache.ReasonInserted},
+		{tempuscache.EventDelete, "other", tempuscache.ReasonDeleted},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("expected %d events, got %d: %+v", len(want), len(got), got)
+	}
+	for i, w := range want {
+		if got[i].Type != w.t || got[i].Key != w.k || got[i].Reason != w.r {
+			t.Fatalf("event %d: expected %v %s %v, got %+v", i, w.t, w.k, w.r, got[i])
+		}
+	}
+	if !got[2].Time.Equal(clock.Now()) {
+		t.Fatalf("expected event time from the injected clock, got %v", got[2].Time)
+	}
+
+	var filtered []string
+	for ev := range removals.C {
+		filtered = append(filtered, ev.Type.String()+" "+ev.Key)
+	}
+	if len(filtered) != 2 || filtered[0] != "expire user:1" || filtered[1] != "evict user:2" {
+		t.Fatalf("unexpected filtered events %v", filtered)
+	}
+}
+
+/*
+TestJanitorTicks verifies that active expiration is driven by the
+injected clock's ticker.
+*/
+
+func TestJanitorTicks(t *testing.T) {
+	swept := make(chan int, 1)
+	cache, clock := newFakeCache(
+		tempuscache.WithCleanupInterval(time.Minute),
+		tempuscache.WithSweepHook(func(_ time.Duration, removed int) {
+			swept <- removed
+		}),
+	)
+	defer cache.Close()
+
+	cache.Set("a", 1, time.Second)
+	cache.Set("b", 1, time.Hour)
+
+	clock.Advance(59 * time.Second)
+	select {
+	case <-swept:
+		t.Fatal("expected no sweep before the interval elapsed")
+	default:
+	}
+
+	clock.Advance(time.Second)
+	if removed := <-swept; removed != 1 {
+		t.Fatalf("expected 1 entry swept, got %d", removed)
+	}
+
+	if stats := cache.Stats(); stats.JanitorExpirations != 1 || stats.Entries != 1 {
+		t.Fatalf("unexpected stats after sweep %+v", stats)
+	}
+}
+
+/*
+TestSetCleanupInterval verifies that the janitor can be enabled,
+restarted and disabled at runtime, including concurrently with
+regular traffic (run with -race).
+*/
+
+func TestSetCleanupInterval(t *testing.T) {
+	swept := make(chan int, 100)
+	cache, clock := newFakeCache(
+		tempuscache.WithSweepHook(func(_ time.Duration, removed int) {
+			swept <- removed
+		}),
+	)
+	defer cache.Close()
+
+	cache.Set("a", 1, time.Millisecond)
+	cache.SetCleanupInterval(time.Millisecond)
+	clock.Advance(2 * time.Millisecond)
+
+	if removed := <-swept; removed != 1 {
+		t.Fatalf("expected janitor to reclaim entry, got %d", removed)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(3)
+		go func(i int) {
+			defer wg.Done()
+			cache.SetCleanupInterval(time.Duration(i+1) * time.Millisecond)
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			cache.Set(fmt.Sprintf("k%d", i), i, time.Millisecond)
+			cache.Get(fmt.Sprintf("k%d", i))
+		}(i)
+		go func() {
+			defer wg.Done()
+			clock.Advance(time.Millisecond)
+		}()
+	}
+	wg.Wait()
+
+	if n := clock.Tickers(); n != 1 {
+		t.Fatalf("expected exactly one janitor ticker, got %d", n)
+	}
+
+	cache.SetCleanupInterval(0)
+	if n := clock.Tickers(); n != 0 {
+		t.Fatalf("expected janitor to be disabled, got %d tickers", n)
+	}
+
+	before := cache.Stats().Entries
+	cache.Set("b", 1, time.Millisecond)
+	clock.Advance(time.Hour)
+	if cache.Stats().Entries != before+1 {
+		t.Fatal("expected no active expiration after disabling the janitor")
+	}
+}
+
+/*
+TestCoarseClock verifies that in coarse mode expiration follows the
+cached timestamp, which only moves when the refresher ticks.
+*/
+
+/*
+TestStopOnlyStopsJanitor verifies that Stop is idempotent, waits for
+the janitor and leaves the cache's contents and API intact.
+*/
+
+func TestStopOnlyStopsJanitor(t *testing.T) {
+	cache, clock := newFakeCache(tempuscache.WithCleanupInterval(time.Second))
+	cache.Set("a", 1, 0)
+
+	cache.Stop()
+	cache.Stop()
+
+	if n := clock.Tickers(); n != 0 {
+		t.Fatalf("expected janitor ticker to be stopped, got %d", n)
+	}
+	if cache.Closed() {
+		t.Fatal("expected Stop not to close the cache")
+	}
+	if v, found := cache.Get("a"); !found || v != 1 {
+		t.Fatalf("expected entries to survive Stop, got %v", v)
+	}
+
+	cache.Set("b", 2, time.Millisecond)
+	clock.Advance(2 * time.Millisecond)
+	if cache.Has("b") {
+		t.Fatal("expected lazy expiration to keep working after Stop")
+	}
+
+	cache.SetCleanupInterval(time.Second)
+	if n := clock.Tickers(); n != 1 {
+		t.Fatalf("expected SetCleanupInterval to restart the janitor, got %d tickers", n)
+	}
+	cache.Close()
+}
+
+func TestCoarseClock(t *testing.T) {
+	cache, clock := newFakeCache(tempuscache.WithCoarseClock(10 * time.Millisecond))
+	defer cache.Close()
+
+	cache.Set("a", 1, 5*time.Millisecond)
+
+	clock.Advance(6 * time.Millisecond)
+	if !cache.Has("a") {
+		t.Fatal("expected entry to live until the cached timestamp is refreshed")
+	}
+
+	clock.Advance(4 * time.Millisecond)
+	// The next tick is only accepted once the previous refresh is stored.
+	clock.Advance(10 * time.Millisecond)
+	if cache.Has("a") {
+		t.Fatal("expected entry to expire after the refresh")
+	}
+
+	cache.Close()
+	if n := clock.Tickers(); n != 0 {
+		t.Fatalf("expected refresher to stop on Close, got %d tickers", n)
+	}
+}
+
+/*
+TestTTLJitter verifies that jittered TTLs stay within range, are
+reproducible under a seeded source and can be overridden per call.
+*/
+
+func TestTTLJitter(t *testing.T) {
+	ttls := func() []time.Duration {
+		cache, _ := newFakeCache(
+			tempuscache.WithTTLJitter(0.2),
+			tempuscache.WithRandSource(rand.NewPCG(1, 2)),
+		)
+
+		var out []time.Duration
+		for i := 0; i < 100; i++ {
+			key := fmt.Sprintf("k%d", i)
+			cache.Set(key, i, time.Hour)
+			ttl, _ := cache.TTL(key)
+			out = append(out, ttl)
+		}
+		return out
+	}
+
+	first, second := ttls(), ttls()
+	distinct := map[time.Duration]bool{}
+	for i, ttl := range first {
+		if ttl <= 48*time.Minute || ttl > time.Hour {
+			t.Fatalf("jittered TTL %v out of range", ttl)
+		}
+		if ttl != second[i] {
+			t.Fatalf("expected reproducible TTLs, got %v and %v", ttl, second[i])
+		}
+		distinct[ttl] = true
+	}
+	if len(distinct) < 90 {
+		t.Fatalf("expected spread-out TTLs, got %d distinct values", len(distinct))
+	}
+
+	cache, _ := newFakeCache(tempuscache.WithTTLJitter(0.5))
+	cache.SetWithJitter("exact", 1, time.Hour, 0)
+	if ttl, _ := cache.TTL("exact"); ttl != time.Hour {
+		t.Fatalf("expected per-call override to disable jitter, got %v", ttl)
+	}
+
+	cache.Set("forever", 1, 0)
+	if ttl, _ := cache.TTL("forever"); ttl != 0 {
+		t.Fatalf("expected persistent key to stay persistent, got %v", ttl)
+	}
+}
+
+/*
+TestTTLPolicy verifies WithDefaultTTL, WithMaxTTL and the
+DefaultExpiration / NoExpiration sentinels on inserts and updates.
+*/
+
+func TestTTLPolicy(t *testing.T) {
+	cache, clock := newFakeCache(
+		tempuscache.WithDefaultTTL(10*time.Minute),
+		tempuscache.WithMaxTTL(time.Hour),
+	)
+
+	ttlOf := func(key string) time.Duration {
+		t.Helper()
+		ttl, found := cache.TTL(key)
+		if !found {
+			t.Fatalf("expected %q to exist", key)
+		}
+		return ttl
+	}
+
+	cache.Set("default", 1, tempuscache.DefaultExpiration)
+	cache.Set("long", 1, 24*time.Hour)
+	cache.Set("forever", 1, tempuscache.NoExpiration)
+
+	if ttl := ttlOf("default"); ttl != 10*time.Minute {
+		t.Fatalf("expected default TTL, got %v", ttl)
+	}
+	if ttl := ttlOf("long"); ttl != time.Hour {
+		t.Fatalf("expected clamped TTL, got %v", ttl)
+	}
+	if ttl := ttlOf("forever"); ttl != time.Hour {
+		t.Fatalf("expected NoExpiration to be clamped, got %v", ttl)
+	}
+
+	// Updates re-apply the policy.
+	clock.Advance(5 * time.Minute)
+	cache.Set("default", 2, tempuscache.DefaultExpiration)
+	if ttl := ttlOf("default"); ttl != 10*time.Minute {
+		t.Fatalf("expected update to re-arm default TTL, got %v", ttl)
+	}
+	cache.SetIfPresent("long", 2, 2*time.Hour)
+	if ttl := ttlOf("long"); ttl != time.Hour {
+		t.Fatalf("expected clamped TTL on update, got %v", ttl)
+	}
+
+	cache.Expire("default", 48*time.Hour)
+	if ttl := ttlOf("default"); ttl != time.Hour {
+		t.Fatalf("expected Expire to be clamped, got %v", ttl)
+	}
+	cache.Persist("default")
+	if ttl := ttlOf("default"); ttl != time.Hour {
+		t.Fatalf("expected Persist to re-arm the maximum, got %v", ttl)
+	}
+
+	// Counters keep their expiration on increment.
+	cache.Incr("hits", time.Minute)
+	clock.Advance(30 * time.Second)
+	cache.Incr("hits", 0)
+	if ttl := ttlOf("hits"); ttl != 30*time.Second {
+		t.Fatalf("expected counter to keep its window, got %v", ttl)
+	}
+
+	plain, _ := newFakeCache()
+	plain.Set("a", 1, time.Minute)
+	plain.Set("a", 2, tempuscache.DefaultExpiration)
+	if ttl, _ := plain.TTL("a"); ttl != time.Minute {
+		t.Fatalf("expected legacy update to keep expiration, got %v", ttl)
+	}
+	plain.Set("a", 3, tempuscache.NoExpiration)
+	if ttl, _ := plain.TTL("a"); ttl != 0 {
+		t.Fatalf("expected NoExpiration to clear expiration, got %v", ttl)
+	}
+
+	// A value replacing a negative entry is capped like a fresh insert.
+	capped, _ := newFakeCache(tempuscache.WithMaxTTL(time.Minute))
+	capped.SetNotFound("k", time.Second)
+	capped.Set("k", 1, tempuscache.DefaultExpiration)
+	if ttl, found := capped.TTL("k"); !found || ttl != time.Minute {
+		t.Fatalf("expected maximum TTL after replacing a negative entry, got %v (%v)", ttl, found)
+	}
+}
+
+/*
+TestInvalidateTagExpired verifies that InvalidateTag reclaims expired
+tagged entries as lazy expirations rather than reporting them as
+invalidated.
+*/
+
+func TestInvalidateTagExpired(t *testing.T) {
+	cache, clock := newFakeCache()
+	sub := cache.Subscribe(tempuscache.EventFilter{Types: tempuscache.EventDelete})
+	defer sub.Unsubscribe()
+
+	cache.SetWithTags("old", 1, time.Second, "t")
+	cache.SetWithTags("live", 2, 0, "t")
+	clock.Advance(time.Minute)
+
+	if n := cache.InvalidateTag("t"); n != 1 {
+		t.Fatalf("expected only the live entry to be invalidated, got %d", n)
+	}
+
+	stats := cache.Stats()
+	if stats.Deletes != 1 || stats.LazyExpirations != 1 {
+		t.Fatalf("expected 1 delete and 1 lazy expiration, got %+v", stats)
+	}
+	if ev := <-sub.C; ev.Key != "live" {
+		t.Fatalf("expected a delete event only for the live entry, got %q", ev.Key)
+	}
+	select {
+	case ev := <-sub.C:
+		t.Fatalf("unexpected delete event %+v", ev)
+	default:
+	}
+}
+
+/*
+TestClearExpired verifies that Clear counts only live entries as
+deletes and reclaims expired ones as lazy expirations.
+*/
+
+func TestClearExpired(t *testing.T) {
+	cache, clock := newFakeCache()
+
+	cache.Set("old", 1, time.Second)
+	cache.Set("live", 2, 0)
+	cache.SetNotFound("missing", 0)
+	clock.Advance(time.Minute)
+
+	if n := cache.Clear(); n != 1 {
+		t.Fatalf("expected 1 cleared live entry, got %d", n)
+	}
+	stats := cache.Stats()
+	if stats.Deletes != 2 || stats.LazyExpirations != 1 || stats.Entries != 0 {
+		t.Fatalf("expected 2 deletes, 1 lazy expiration and no entries, got %+v", stats)
+	}
+}
@@ -0,0 +1,205 @@
+package tempuscache
+
+import "time"
+
+/*
+Conditional writes provide atomic read-modify-write primitives.
+
+================================================================================
+MOTIVATION
+================================================================================
+
+Building rate limiters, idempotency guards or distributed-lock style
+helpers on top of Get + Set is racy:
+
+    if _, found := cache.Get(key); !found {
+        cache.Set(key, value, ttl)   // another goroutine may win here
+    }
+
+The check and the write happen under two separate lock acquisitions,
+so two callers can both observe "absent" and both write.
+
+Every operation in this file performs its check and its mutation
+under a single exclusive Lock(), making them linearizable with
+respect to all other cache operations.
+
+================================================================================
+VERSIONING (CAS TOKENS)
+================================================================================
+
+Each successful write stamps the Item with a new value taken from a
+cache-wide monotonically increasing counter.
+
+- GetWithVersion returns the value together with its current version.
+- CompareAndSwap only writes if the stored version is still the same.
+
+Any intervening write (Set, Update, another CAS, ...) bumps the version,
+so a stale token is always rejected.
+
+================================================================================
+EXPIRATION SEMANTICS
+================================================================================
+
+Expired entries are treated as absent. They are lazily removed
+during the check, identical to the behavior of Get().
+
+Conditional operations do NOT update hit/miss statistics; they are
+write primitives, not lookups.
+*/
+
+/*
+SetIfAbsent stores value only if key does not exist (or has expired).
+
+RETURNS:
+- true  -> The value was stored.
+- false -> A live entry already exists; the cache is unchanged.
+
+Equivalent to Redis SETNX with an expiration.
+*/
+
+func (c *Cache) SetIfAbsent(key string, value interface{}, ttl time.Duration) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return false
+	}
+
+	if _, found := c.lookupValue(key); found {
+		return false
+	}
+
+	c.set(key, value, ttl)
+	return true
+}
+
+/*
+SetIfPresent replaces the value of key only if a live entry exists.
+
+RETURNS:
+- true  -> The value was replaced.
+- false -> The key is missing or expired; nothing is stored.
+
+TTL handling matches Set (see ttlpolicy.go for ttl == 0).
+*/
+
+func (c *Cache) SetIfPresent(key string, value interface{}, ttl time.Duration) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, found := c.lookupValue(key); !found {
+		return false
+	}
+
+	c.set(key, value, ttl)
+	return true
+}
+
+/*
+GetWithVersion behaves like Get but additionally returns the
+current version (CAS token) of the entry.
+
+RETURNS:
+- (value, version, true) -> Key exists and is not expired
+- (nil, 0, false)        -> Key does not exist or is expired
+
+The returned version is meant to be passed to CompareAndSwap.
+Like Get, this marks the entry as recently used and updates statistics.
+*/
+
+func (c *Cache) GetWithVersion(key string) (interface{}, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, found := c.lookup(key)
+	if !found {
+		c.stats.misses.Add(1)
+		return nil, 0, false
+	}
+
+	c.store.moveToFront(item)
+	if item.negative {
+		c.stats.negativeHits.Add(1)
+		return nil, 0, false
+	}
+
+	c.stats.hits.Add(1)
+	c.recordAccess(item)
+	return item.value, item.version, true
+}
+
+/*
+CompareAndSwap replaces the value of key only if its current version
+equals the supplied token.
+
+PARAMETERS:
+- key     : Target key
+- version : Token previously obtained from GetWithVersion
+- value   : New value
+- ttl     : New TTL (same rules as Set)
+
+RETURNS:
+- true  -> The swap succeeded; the entry now carries a new version.
+- false -> The key is missing, expired, or was modified since the
+           token was issued.
+*/
+
+func (c *Cache) CompareAndSwap(key string, version uint64, value interface{}, ttl time.Duration) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, found := c.lookupValue(key)
+	if !found || item.version != version {
+		return false
+	}
+
+	c.set(key, value, ttl)
+	return true
+}
+
+/*
+Update atomically computes a new value for key.
+
+PARAMETERS:
+- key : Target key
+- ttl : TTL applied when the result is stored (same rules as Set)
+- fn  : Callback receiving the current value and whether it exists.
+        It returns the new value and whether it should be stored.
+
+BEHAVIOR:
+
+1. The current live value (if any) is passed to fn.
+2. If fn returns store == false → the cache is left untouched.
+3. Otherwise the returned value is written via the regular Set path
+   (including LRU promotion and capacity eviction).
+
+RETURNS:
+The value now associated with key and whether such a value exists.
+
+IMPORTANT:
+fn runs while the cache lock is held. It must be fast and must NOT
+call back into the same Cache, or it will deadlock.
+*/
+
+func (c *Cache) Update(key string, ttl time.Duration, fn func(old interface{}, found bool) (interface{}, bool)) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, false
+	}
+
+	var old interface{}
+	item, found := c.lookupValue(key)
+	if found {
+		old = item.value
+	}
+
+	value, store := fn(old, found)
+	if !store {
+		return old, found
+	}
+
+	c.set(key, value, ttl)
+	return value, true
+}
@@ -0,0 +1,215 @@
+package tempuscache
+
+import (
+	"math"
+	"time"
+)
+
+/*
+Atomic numeric counters.
+
+================================================================================
+PURPOSE
+================================================================================
+
+Rate limiters and quota trackers need "read current count, add one,
+write back" to be a single atomic step. Incr / IncrBy / Decr /
+IncrByFloat perform the whole read-modify-write under one exclusive
+Lock(), mirroring the semantics of the Redis INCR family.
+
+================================================================================
+SEMANTICS
+================================================================================
+
+1. Missing (or expired) key:
+   - The counter starts at zero, delta is applied, and the result
+     is stored with the supplied ttl.
+
+2. Existing key:
+   - delta is added to the stored value.
+   - The ORIGINAL expiration is preserved; ttl is ignored.
+     (A fixed window rate limiter therefore resets on schedule,
+     no matter how many increments happen inside the window.)
+
+3. Type handling:
+   - Integer counters accept any signed integer type (int, int8,
+     int16, int32, int64). The stored type is preserved.
+   - New integer counters are stored as int64.
+   - IncrByFloat accepts float32/float64 and signed integers and
+     always stores the result as float64.
+
+4. Errors:
+   - ErrNotInteger / ErrNotFloat if the stored value has an
+     unsupported type. The entry is left unchanged.
+   - ErrOverflow if the result does not fit the stored type.
+   - ErrClosed if the cache has been closed.
+
+Counter operations do not update hit/miss statistics.
+*/
+
+/*
+Incr increments the integer stored at key by one.
+See IncrBy for full semantics.
+*/
+
+func (c *Cache) Incr(key string, ttl time.Duration) (int64, error) {
+	return c.IncrBy(key, 1, ttl)
+}
+
+/*
+Decr decrements the integer stored at key by one.
+See IncrBy for full semantics.
+*/
+
+func (c *Cache) Decr(key string, ttl time.Duration) (int64, error) {
+	return c.IncrBy(key, -1, ttl)
+}
+
+/*
+IncrBy atomically adds delta to the integer stored at key and
+returns the new value.
+
+If the key does not exist, it is created with value delta and the
+given ttl. Otherwise the existing expiration is kept.
+*/
+
+func (c *Cache) IncrBy(key string, delta int64, ttl time.Duration) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return 0, ErrClosed
+	}
+
+	item, found := c.lookupValue(key)
+	if !found {
+		c.set(key, delta, ttl)
+		return delta, nil
+	}
+
+	value, result, err := addInt(item.value, delta)
+	if err != nil {
+		return 0, err
+	}
+
+	c.setExact(key, value, 0)
+	return result, nil
+}
+
+/*
+IncrByFloat atomically adds delta to the number stored at key and
+returns the new value.
+
+If the key does not exist, it is created with value delta and the
+given ttl. Otherwise the existing expiration is kept.
+
+The result is always stored as float64.
+*/
+
+func (c *Cache) IncrByFloat(key string, delta float64, ttl time.Duration) (float64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return 0, ErrClosed
+	}
+
+	item, found := c.lookupValue(key)
+	if !found {
+		c.set(key, delta, ttl)
+		return delta, nil
+	}
+
+	var current float64
+	switch v := item.value.(type) {
+	case float64:
+		current = v
+	case float32:
+		current = float64(v)
+	default:
+		n, ok := toInt64(v)
+		if !ok {
+			return 0, ErrNotFloat
+		}
+		current = float64(n)
+	}
+
+	result := current + delta
+	if math.IsNaN(result) || math.IsInf(result, 0) {
+		return 0, ErrOverflow
+	}
+
+	c.setExact(key, result, 0)
+	return result, nil
+}
+
+/*
+toInt64 widens any signed integer type to int64.
+*/
+
+func toInt64(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), true
+	case int8:
+		return int64(n), true
+	case int16:
+		return int64(n), true
+	case int32:
+		return int64(n), true
+	case int64:
+		return n, true
+	}
+	return 0, false
+}
+
+/*
+addInt adds delta to v while preserving v's concrete type.
+
+RETURNS:
+- The new value in the original type (ready to be stored).
+- The new value widened to int64 (returned to the caller).
+- ErrNotInteger or ErrOverflow on failure.
+*/
+
+func addInt(v interface{}, delta int64) (interface{}, int64, error) {
+	current, ok := toInt64(v)
+	if !ok {
+		return nil, 0, ErrNotInteger
+	}
+
+	if (delta > 0 && current > math.MaxInt64-delta) ||
+		(delta < 0 && current < math.MinInt64-delta) {
+		return nil, 0, ErrOverflow
+	}
+	result := current + delta
+
+	var min, max int64
+	switch v.(type) {
+	case int:
+		min, max = math.MinInt, math.MaxInt
+	case int8:
+		min, max = math.MinInt8, math.MaxInt8
+	case int16:
+		min, max = math.MinInt16, math.MaxInt16
+	case int32:
+		min, max = math.MinInt32, math.MaxInt32
+	case int64:
+		return result, result, nil
+	}
+
+	if result < min || result > max {
+		return nil, 0, ErrOverflow
+	}
+
+	switch v.(type) {
+	case int:
+		return int(result), result, nil
+	case int8:
+		return int8(result), result, nil
+	case int16:
+		return int16(result), result, nil
+	default:
+		return int32(result), result, nil
+	}
+}
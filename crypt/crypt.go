@@ -0,0 +1,330 @@
+/*
+Package crypt provides AES-GCM encryption at rest for data produced
+from a TempusCache instance.
+
+================================================================================
+SCOPE
+================================================================================
+
+Anything a cache writes outside the process (snapshot files, on-disk
+or remote second-level tiers) may contain PII and must be unreadable
+without the key. This package is the encryption layer such writers
+build on: they hand each serialized record to a Sealer before it
+leaves the process and back to the Sealer when it is read.
+
+	ring, _ := crypt.NewKeyRing("2024-01", key)
+	sealer := crypt.NewSealer(ring)
+
+	blob, _ := sealer.Seal(record, []byte("users"))   // write blob to disk
+	record, _ = sealer.Open(blob, []byte("users"))
+
+The associated data (here the cache name) is authenticated but not
+encrypted, binding a record to its context: a blob copied into a
+different cache's file fails to open.
+
+================================================================================
+RECORD FORMAT
+================================================================================
+
+	+---------+-------+--------+-------+------------------------+
+	| version | idLen | key id | nonce | ciphertext ‖ GCM tag   |
+	| 1 byte  | 1 byte|        | 12 B  |                        |
+	+---------+-------+--------+-------+------------------------+
+
+Every record gets a fresh random 96-bit nonce. The header (version,
+key id) is part of the authenticated data, so it cannot be altered
+without Open failing.
+
+================================================================================
+KEY ROTATION
+================================================================================
+
+Keys are addressed by id through a KeyProvider. Seal always uses the
+provider's CURRENT key and records its id; Open looks up whichever key
+the record names. Rotating therefore never breaks existing data:
+
+ 1. ring.Rotate("2024-02", newKey)  → new records use the new key
+ 2. Reseal(old) re-encrypts old records under the current key
+    (e.g. while compacting a snapshot)
+ 3. ring.Retire("2024-01") once nothing references the old key
+
+Applications holding keys in a KMS or secret manager implement
+KeyProvider themselves.
+*/
+package crypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const version = 1
+
+var (
+	// ErrUnknownKey is returned by Open when the record names a key id
+	// the provider does not know (e.g. it was retired).
+	ErrUnknownKey = errors.New("crypt: unknown key id")
+
+	// ErrMalformed is returned by Open for data that is not a sealed
+	// record of a supported version.
+	ErrMalformed = errors.New("crypt: malformed record")
+
+	// ErrDecrypt is returned by Open when authentication fails: wrong
+	// key, wrong associated data, or tampered ciphertext.
+	ErrDecrypt = errors.New("crypt: message authentication failed")
+)
+
+/*
+KeyProvider supplies encryption keys by id.
+
+Keys must be 16, 24 or 32 bytes (AES-128/192/256). Ids must be
+1–255 bytes. Implementations must be safe for concurrent use.
+*/
+
+type KeyProvider interface {
+	// CurrentKey returns the key new records are sealed with.
+	CurrentKey() (id string, key []byte, err error)
+
+	// Key returns the key with the given id.
+	Key(id string) ([]byte, error)
+}
+
+/*
+KeyRing is an in-memory KeyProvider with rotation.
+*/
+
+type KeyRing struct {
+	mu      sync.RWMutex
+	current string
+	keys    map[string][]byte
+}
+
+var _ KeyProvider = (*KeyRing)(nil)
+
+/*
+NewKeyRing returns a KeyRing whose current key is key.
+*/
+
+func NewKeyRing(id string, key []byte) (*KeyRing, error) {
+	r := &KeyRing{keys: make(map[string][]byte)}
+	if err := r.Rotate(id, key); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+/*
+Rotate adds key under id and makes it current. Previous keys remain
+available for Open until retired. Ids must be new: reusing one,
+including the current id, is an error.
+*/
+
+func (r *KeyRing) Rotate(id string, key []byte) error {
+	if err := checkKey(id, key); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Re-keying an existing id would make every record sealed under it
+	// unreadable, so ids are never reused.
+	if _, found := r.keys[id]; found {
+		return fmt.Errorf("crypt: key id %q already exists", id)
+	}
+	r.keys[id] = append([]byte(nil), key...)
+	r.current = id
+	return nil
+}
+
+/*
+Retire removes a non-current key. Records sealed with it can no
+longer be opened.
+*/
+
+func (r *KeyRing) Retire(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if id == r.current {
+		return fmt.Errorf("crypt: cannot retire current key %q", id)
+	}
+	delete(r.keys, id)
+	return nil
+}
+
+func (r *KeyRing) CurrentKey() (string, []byte, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.current, r.keys[r.current], nil
+}
+
+func (r *KeyRing) Key(id string) ([]byte, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key, found := r.keys[id]
+	if !found {
+		return nil, ErrUnknownKey
+	}
+	return key, nil
+}
+
+/*
+Sealer encrypts and decrypts records with keys from a KeyProvider.
+It is safe for concurrent use.
+*/
+
+type Sealer struct {
+	keys KeyProvider
+}
+
+/*
+NewSealer returns a Sealer using keys from p.
+*/
+
+func NewSealer(p KeyProvider) *Sealer {
+	return &Sealer{keys: p}
+}
+
+/*
+Seal encrypts plaintext with the current key, authenticating
+additionalData along with it.
+*/
+
+func (s *Sealer) Seal(plaintext, additionalData []byte) ([]byte, error) {
+	id, key, err := s.keys.CurrentKey()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkKey(id, key); err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 0, 2+len(id)+aead.NonceSize()+len(plaintext)+aead.Overhead())
+	header = append(header, version, byte(len(id)))
+	header = append(header, id...)
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	out := append(header, nonce...)
+	return aead.Seal(out, nonce, plaintext, aad(header, additionalData)), nil
+}
+
+/*
+Open authenticates and decrypts a record produced by Seal, using
+the key the record names.
+*/
+
+func (s *Sealer) Open(record, additionalData []byte) ([]byte, error) {
+	header, id, rest, err := parse(record)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keys.Key(id)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) < aead.NonceSize()+aead.Overhead() {
+		return nil, ErrMalformed
+	}
+
+	nonce, ciphertext := rest[:aead.NonceSize()], rest[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, aad(header, additionalData))
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
+
+/*
+Reseal re-encrypts a record under the current key. Records already
+sealed with the current key are returned unchanged.
+*/
+
+func (s *Sealer) Reseal(record, additionalData []byte) ([]byte, error) {
+	_, id, _, err := parse(record)
+	if err != nil {
+		return nil, err
+	}
+
+	current, _, err := s.keys.CurrentKey()
+	if err != nil {
+		return nil, err
+	}
+	if id == current {
+		return record, nil
+	}
+
+	plaintext, err := s.Open(record, additionalData)
+	if err != nil {
+		return nil, err
+	}
+	return s.Seal(plaintext, additionalData)
+}
+
+/*
+KeyID returns the id of the key a record was sealed with.
+*/
+
+func KeyID(record []byte) (string, error) {
+	_, id, _, err := parse(record)
+	return id, err
+}
+
+func parse(record []byte) (header []byte, id string, rest []byte, err error) {
+	if len(record) < 2 || record[0] != version {
+		return nil, "", nil, ErrMalformed
+	}
+	n := int(record[1])
+	if n == 0 || len(record) < 2+n {
+		return nil, "", nil, ErrMalformed
+	}
+	return record[:2+n], string(record[2 : 2+n]), record[2+n:], nil
+}
+
+/*
+aad binds the record header to the caller's associated data.
+*/
+
+func aad(header, additionalData []byte) []byte {
+	return append(append([]byte(nil), header...), additionalData...)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func checkKey(id string, key []byte) error {
+	if len(id) == 0 || len(id) > 255 {
+		return fmt.Errorf("crypt: key id must be 1-255 bytes, got %d", len(id))
+	}
+	switch len(key) {
+	case 16, 24, 32:
+		return nil
+	}
+	return fmt.Errorf("crypt: invalid AES key size %d", len(key))
+}
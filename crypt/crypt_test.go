@@ -0,0 +1,146 @@
+package crypt
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func key(b byte) []byte {
+	return bytes.Repeat([]byte{b}, 32)
+}
+
+func TestSealOpen(t *testing.T) {
+	ring, err := NewKeyRing("k1", key(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealer := NewSealer(ring)
+
+	plaintext := []byte(`{"email":"alice@example.com"}`)
+	record, err := sealer.Seal(plaintext, []byte("users"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bytes.Contains(record, []byte("alice")) {
+		t.Fatal("expected plaintext not to appear in the record")
+	}
+
+	got, err := sealer.Open(record, []byte("users"))
+	if err != nil || !bytes.Equal(got, plaintext) {
+		t.Fatalf("expected round-trip, got %q (%v)", got, err)
+	}
+
+	again, _ := sealer.Seal(plaintext, []byte("users"))
+	if bytes.Equal(record, again) {
+		t.Fatal("expected a fresh nonce per record")
+	}
+}
+
+/*
+TestTamperDetection verifies that any change to the record or its
+context is rejected.
+*/
+
+func TestTamperDetection(t *testing.T) {
+	ring, _ := NewKeyRing("k1", key(1))
+	sealer := NewSealer(ring)
+	record, _ := sealer.Seal([]byte("secret"), []byte("users"))
+
+	if _, err := sealer.Open(record, []byte("orders")); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("expected wrong associated data to fail, got %v", err)
+	}
+
+	for i := range record {
+		tampered := append([]byte(nil), record...)
+		tampered[i] ^= 0x01
+		if _, err := sealer.Open(tampered, []byte("users")); err == nil {
+			t.Fatalf("expected tampering with byte %d to be detected", i)
+		}
+	}
+
+	other := NewSealer(must(NewKeyRing("k1", key(2))))
+	if _, err := other.Open(record, []byte("users")); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("expected wrong key to fail, got %v", err)
+	}
+
+	if _, err := sealer.Open([]byte{9, 9}, nil); !errors.Is(err, ErrMalformed) {
+		t.Fatalf("expected malformed record error, got %v", err)
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	ring, _ := NewKeyRing("k1", key(1))
+	sealer := NewSealer(ring)
+	old, _ := sealer.Seal([]byte("v1"), nil)
+
+	if err := ring.Rotate("k2", key(2)); err != nil {
+		t.Fatal(err)
+	}
+
+	fresh, _ := sealer.Seal([]byte("v2"), nil)
+	if id, _ := KeyID(fresh); id != "k2" {
+		t.Fatalf("expected new records to use k2, got %s", id)
+	}
+	if got, err := sealer.Open(old, nil); err != nil || string(got) != "v1" {
+		t.Fatalf("expected old record to stay readable, got %q (%v)", got, err)
+	}
+
+	resealed, err := sealer.Reseal(old, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, _ := KeyID(resealed); id != "k2" {
+		t.Fatalf("expected resealed record to use k2, got %s", id)
+	}
+	if same, _ := sealer.Reseal(fresh, nil); !bytes.Equal(same, fresh) {
+		t.Fatal("expected current-key record to be returned unchanged")
+	}
+
+	if err := ring.Retire("k2"); err == nil {
+		t.Fatal("expected retiring the current key to fail")
+	}
+	if err := ring.Retire("k1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sealer.Open(old, nil); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("expected retired key to be unknown, got %v", err)
+	}
+	if got, err := sealer.Open(resealed, nil); err != nil || string(got) != "v1" {
+		t.Fatalf("expected resealed record to survive retirement, got %q (%v)", got, err)
+	}
+}
+
+func TestInvalidKeys(t *testing.T) {
+	if _, err := NewKeyRing("k1", []byte("short")); err == nil {
+		t.Fatal("expected invalid key size to be rejected")
+	}
+	if _, err := NewKeyRing("", key(1)); err == nil {
+		t.Fatal("expected empty key id to be rejected")
+	}
+
+	ring, _ := NewKeyRing("k1", key(1))
+	if err := ring.Rotate("k2", key(2)); err != nil {
+		t.Fatal(err)
+	}
+	if err := ring.Rotate("k1", key(3)); err == nil {
+		t.Fatal("expected reusing a key id to be rejected")
+	}
+
+	sealer := NewSealer(ring)
+	record, _ := sealer.Seal([]byte("v"), nil)
+	if err := ring.Rotate("k2", key(4)); err == nil {
+		t.Fatal("expected re-keying the current id to be rejected")
+	}
+	if _, err := sealer.Open(record, nil); err != nil {
+		t.Fatalf("expected records under the current id to stay readable, got %v", err)
+	}
+}
+
+func must(r *KeyRing, err error) *KeyRing {
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
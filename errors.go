@@ -0,0 +1,40 @@
+package tempuscache
+
+import "errors"
+
+/*
+Sentinel errors returned by TempusCache operations.
+
+================================================================================
+ERROR MODEL
+================================================================================
+
+All errors are package-level sentinels so callers can match them
+with errors.Is():
+
+    if errors.Is(err, tempuscache.ErrNotInteger) {
+        ...
+    }
+
+Operations that cannot fail (Set, Get, Delete, ...) keep their
+error-free signatures; only operations with genuine failure modes
+return an error.
+*/
+
+var (
+	// ErrNotInteger is returned by integer counter operations when the
+	// stored value is not a signed integer type.
+	ErrNotInteger = errors.New("tempuscache: value is not an integer")
+
+	// ErrNotFloat is returned by IncrByFloat when the stored value is
+	// neither a float nor a signed integer type.
+	ErrNotFloat = errors.New("tempuscache: value is not a float")
+
+	// ErrOverflow is returned when an increment would overflow the
+	// stored integer type.
+	ErrOverflow = errors.New("tempuscache: increment would overflow")
+
+	// ErrClosed is returned by operations invoked after Close or
+	// Shutdown.
+	ErrClosed = errors.New("tempuscache: cache is closed")
+)
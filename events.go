@@ -0,0 +1,352 @@
+package tempuscache
+
+import (
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+/*
+Event stream / change subscription API.
+
+================================================================================
+MOTIVATION
+================================================================================
+
+Audit logs, local secondary indexes and replication all need to know
+WHAT changed in the cache and WHY — without patching Set or
+removeElement. Subscribe exposes every mutation as an Event on a
+channel:
+
+    sub := cache.Subscribe(EventFilter{Types: EventDelete | EventEvict})
+    defer sub.Unsubscribe()
+
+    for ev := range sub.C {
+        log.Printf("%s %s (%s)", ev.Type, ev.Key, ev.Reason)
+    }
+
+================================================================================
+EVENT SOURCES
+================================================================================
+
+- set()           → EventSet    (ReasonInserted / ReasonUpdated)
+- removeElement() → EventDelete (ReasonDeleted / ReasonInvalidated /
+                                 ReasonCleared)
+                    EventExpire (ReasonExpiredLazy / ReasonExpiredJanitor)
+                    EventEvict  (ReasonCapacity / ReasonResized)
+
+Because all removals funnel through removeElement, no mutation path
+can forget to publish.
+
+================================================================================
+DELIVERY GUARANTEES
+================================================================================
+
+Events are published while the cache lock is held, so each
+subscriber observes mutations in the exact order they were applied.
+
+Publishing NEVER blocks the cache. Each subscriber owns a bounded
+buffer; when it is full the DropPolicy decides what is lost:
+
+- DropNewest → The incoming event is discarded (default).
+- DropOldest → The oldest buffered event is discarded to make room.
+
+Subscription.Dropped() reports how many events were lost, so
+consumers can detect gaps and resynchronize.
+
+================================================================================
+COST
+================================================================================
+
+With no subscribers, publishing is a single length check.
+*/
+
+/*
+EventType identifies the kind of mutation. Values are bit flags so
+they can be combined in an EventFilter.
+*/
+
+type EventType uint8
+
+const (
+	EventSet EventType = 1 << iota
+	EventDelete
+	EventExpire
+	EventEvict
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventSet:
+		return "set"
+	case EventDelete:
+		return "delete"
+	case EventExpire:
+		return "expire"
+	case EventEvict:
+		return "evict"
+	}
+	return "unknown"
+}
+
+/*
+Reason explains why an event happened.
+*/
+
+type Reason uint8
+
+const (
+	ReasonInserted       Reason = iota + 1 // new key stored
+	ReasonUpdated                          // existing key overwritten
+	ReasonDeleted                          // explicit deletion
+	ReasonExpiredLazy                      // TTL elapsed, found on access
+	ReasonExpiredJanitor                   // TTL elapsed, found by janitor
+	ReasonCapacity                         // LRU eviction at maxEntries
+	ReasonInvalidated                      // removed by InvalidateTag
+	ReasonCleared                          // removed by Clear
+	ReasonResized                          // evicted by Resize
+)
+
+func (r Reason) String() string {
+	switch r {
+	case ReasonInserted:
+		return "inserted"
+	case ReasonUpdated:
+		return "updated"
+	case ReasonDeleted:
+		return "deleted"
+	case ReasonExpiredLazy:
+		return "expired_lazy"
+	case ReasonExpiredJanitor:
+		return "expired_janitor"
+	case ReasonCapacity:
+		return "capacity"
+	case ReasonInvalidated:
+		return "invalidated"
+	case ReasonCleared:
+		return "cleared"
+	case ReasonResized:
+		return "resized"
+	}
+	return "unknown"
+}
+
+/*
+eventType maps a removal reason to the event type it produces.
+*/
+
+func (r Reason) eventType() EventType {
+	switch r {
+	case ReasonExpiredLazy, ReasonExpiredJanitor:
+		return EventExpire
+	case ReasonCapacity, ReasonResized:
+		return EventEvict
+	case ReasonInserted, ReasonUpdated:
+		return EventSet
+	}
+	return EventDelete
+}
+
+/*
+Event describes a single cache mutation.
+*/
+
+type Event struct {
+	Type   EventType
+	Key    string
+	Reason Reason
+	Time   time.Time
+}
+
+/*
+EventFilter selects which events a subscriber receives.
+
+Types  → Bitmask of EventType values (0 = all types)
+Prefix → Only keys starting with Prefix ("" = all keys)
+*/
+
+type EventFilter struct {
+	Types  EventType
+	Prefix string
+}
+
+func (f EventFilter) match(t EventType, key string) bool {
+	if f.Types != 0 && f.Types&t == 0 {
+		return false
+	}
+	return strings.HasPrefix(key, f.Prefix)
+}
+
+/*
+DropPolicy decides which event is lost when a subscriber's buffer
+is full.
+*/
+
+type DropPolicy uint8
+
+const (
+	DropNewest DropPolicy = iota
+	DropOldest
+)
+
+/*
+DefaultEventBuffer is the per-subscriber buffer size used when
+WithEventBuffer is not supplied.
+*/
+
+const DefaultEventBuffer = 256
+
+/*
+SubscribeOption configures a single subscription.
+*/
+
+type SubscribeOption func(*Subscription)
+
+/*
+WithEventBuffer sets the subscriber's channel capacity (n > 0).
+*/
+
+func WithEventBuffer(n int) SubscribeOption {
+	return func(s *Subscription) {
+		if n > 0 {
+			s.buffer = n
+		}
+	}
+}
+
+/*
+WithDropPolicy sets the behavior when the subscriber falls behind.
+*/
+
+func WithDropPolicy(p DropPolicy) SubscribeOption {
+	return func(s *Subscription) {
+		s.policy = p
+	}
+}
+
+/*
+Subscription is a live event feed.
+
+C is closed after Unsubscribe, terminating any range loop over it.
+*/
+
+type Subscription struct {
+	C <-chan Event
+
+	ch      chan Event
+	cache   *Cache
+	filter  EventFilter
+	buffer  int
+	policy  DropPolicy
+	dropped atomic.Uint64
+	closed  bool
+}
+
+/*
+Subscribe registers a new event subscriber.
+
+Only mutations that happen after Subscribe returns are delivered.
+*/
+
+func (c *Cache) Subscribe(filter EventFilter, opts ...SubscribeOption) *Subscription {
+	s := &Subscription{
+		cache:  c,
+		filter: filter,
+		buffer: DefaultEventBuffer,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.ch = make(chan Event, s.buffer)
+	s.C = s.ch
+
+	c.mu.Lock()
+	if c.closed {
+		s.closed = true
+		close(s.ch)
+	} else {
+		c.subs = append(c.subs, s)
+	}
+	c.mu.Unlock()
+
+	return s
+}
+
+/*
+Unsubscribe stops delivery and closes C. It is safe to call more
+than once. Events still buffered in C can be drained afterwards.
+*/
+
+func (s *Subscription) Unsubscribe() {
+	c := s.cache
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+	s.closed = true
+
+	for i, sub := range c.subs {
+		if sub == s {
+			c.subs = append(c.subs[:i], c.subs[i+1:]...)
+			break
+		}
+	}
+	close(s.ch)
+}
+
+/*
+Dropped returns the number of events lost because the buffer was full.
+*/
+
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+/*
+publish delivers an event to all matching subscribers without blocking.
+
+NOTE:
+The caller must hold the exclusive lock. This both orders events and
+guarantees no subscription is closed mid-send.
+*/
+
+func (c *Cache) publish(t EventType, key string, reason Reason) {
+	if len(c.subs) == 0 {
+		return
+	}
+
+	ev := Event{Type: t, Key: key, Reason: reason, Time: c.clock.Now()}
+
+	for _, s := range c.subs {
+		if !s.filter.match(t, key) {
+			continue
+		}
+		s.deliver(ev)
+	}
+}
+
+func (s *Subscription) deliver(ev Event) {
+	select {
+	case s.ch <- ev:
+		return
+	default:
+	}
+
+	if s.policy == DropOldest {
+		select {
+		case <-s.ch:
+			s.dropped.Add(1)
+		default:
+		}
+		// publish runs under the cache lock, so this is the only
+		// sender and a slot is now guaranteed to be free.
+		s.ch <- ev
+		return
+	}
+
+	s.dropped.Add(1)
+}
@@ -1,7 +1,5 @@
 package tempuscache
 
-import "container/list"
-
 /*
 evictOldest removes the least recently used (LRU) entry
 from the cache when capacity constraints are exceeded.
@@ -37,16 +35,16 @@ The use of a doubly linked list ensures constant-time removal.
 */
 
 func (c *Cache) evictOldest() {
-	elem := c.lru.Back()
-	if elem != nil {
-		c.removeElement(elem)
-		c.stats.Evictions++
+	item := c.store.back()
+	if item != nil {
+		c.removeElement(item, ReasonCapacity)
+		c.stats.capacityEvictions.Add(1)
 	}
 }
 
 /*
-removeElement removes a given list element from both
-the LRU list and the primary storage map.
+removeElement removes a given item from the store (both
+the LRU order and the key index).
 
 ================================================================================
 RESPONSIBILITY
@@ -57,7 +55,7 @@ This is an internal helper method used by:
 - LRU eviction
 - Lazy expiration
 - Active expiration (janitor)
-- Explicit delete (if implemented using it)
+- Explicit delete (Delete, DeleteMany)
 
 ================================================================================
 CONSISTENCY GUARANTEE
@@ -65,8 +63,15 @@ CONSISTENCY GUARANTEE
 
 To maintain structural integrity:
 
-- The element is first removed from the linked list.
-- The corresponding key is then deleted from the map.
+- The Entries and Cost gauges are decremented (including the
+  owning namespace's, if the entry is still current there).
+- The key is dropped from the tag index and the key index.
+- A removal event carrying `reason` is published to subscribers.
+- Finally the item is removed from the store. This must come last:
+  a slab store recycles the item's memory immediately.
+
+Because every removal path funnels through here, it is the single
+place where per-entry bookkeeping must be undone.
 
 This ensures there are no dangling references between
 the list and the hash map.
@@ -80,8 +85,56 @@ the appropriate lock (Lock or RLock upgrade scenario).
 It does NOT perform its own synchronization.
 */
 
-func (c *Cache) removeElement(e *list.Element) {
-	c.lru.Remove(e)
-	item := e.Value.(*Item)
-	delete(c.data, item.key)
+func (c *Cache) removeElement(item *Item, reason Reason) {
+	c.stats.entries.Add(-1)
+	c.stats.cost.Add(-item.cost)
+	detachNamespace(item)
+	c.untag(item)
+	if c.index != nil {
+		c.index.remove(item.key)
+	}
+	c.publish(reason.eventType(), item.key, reason)
+	c.store.remove(item)
+}
+
+/*
+Resize changes the maximum number of entries at runtime.
+
+================================================================================
+BEHAVIOR
+================================================================================
+
+If n > 0:
+    - The new limit applies to all subsequent inserts.
+    - When shrinking below the current size, least recently used
+      entries are evicted IMMEDIATELY until the cache fits.
+    - Each such eviction is counted in Stats.ResizeEvictions and
+      published as EventEvict with ReasonResized.
+
+If n <= 0:
+    - The capacity limit is removed (unbounded cache).
+
+RETURNS:
+The number of entries evicted by this call.
+
+TIME COMPLEXITY:
+O(k) for k evicted entries.
+*/
+
+func (c *Cache) Resize(n int) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.maxEntries = n
+	if n <= 0 {
+		return 0
+	}
+
+	evicted := 0
+	for c.store.len() > n {
+		c.removeElement(c.store.back(), ReasonResized)
+		c.stats.resizeEvictions.Add(1)
+		evicted++
+	}
+	return evicted
 }
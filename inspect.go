@@ -0,0 +1,166 @@
+package tempuscache
+
+import "iter"
+
+/*
+Side-effect-free inspection API.
+
+================================================================================
+MOTIVATION
+================================================================================
+
+Get is a "use" of the entry, not just a read:
+
+- It moves the entry to the front of the LRU list.
+- It deletes the entry if it has expired.
+- It increments Hits / Misses.
+
+Diagnostics, health checks and admin endpoints that call Get
+therefore distort both eviction order and the metrics they are
+trying to observe.
+
+The methods in this file are pure reads:
+
+- They run under RLock(), so they never block each other.
+- They never reorder, delete or count anything.
+- Expired entries are SKIPPED (treated as absent) but left in place
+  for lazy or active expiration to reclaim later.
+
+================================================================================
+ITERATION ORDER
+================================================================================
+
+Keys, Range and All visit entries from most to least recently used.
+
+================================================================================
+CALLBACK CONTRACT
+================================================================================
+
+Range and All copy the live entries under the read lock and invoke
+the callback AFTER releasing it. Callbacks may therefore call any
+method, including Set and Delete, but they observe a snapshot: changes
+made during iteration are not reflected in the remaining entries.
+*/
+
+/*
+Peek returns the value for key without promoting it in the LRU list
+and without updating statistics.
+
+RETURNS:
+- (value, true) -> Key exists and is not expired
+- (nil, false)  -> Key does not exist or is expired
+*/
+
+func (c *Cache) Peek(key string) (interface{}, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	item, found := c.store.get(key)
+	if !found {
+		return nil, false
+	}
+
+	if item.hidden(c.now()) {
+		return nil, false
+	}
+	return item.value, true
+}
+
+/*
+Has reports whether key exists and has not expired.
+*/
+
+func (c *Cache) Has(key string) bool {
+	_, found := c.Peek(key)
+	return found
+}
+
+/*
+Len returns the number of live (non-expired) entries.
+
+TIME COMPLEXITY:
+O(n) — every entry's expiration is checked so that expired entries
+awaiting cleanup are not counted.
+*/
+
+func (c *Cache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := c.now()
+	n := 0
+	c.store.forEach(func(item *Item) bool {
+		if !item.hidden(now) {
+			n++
+		}
+		return true
+	})
+	return n
+}
+
+/*
+Keys returns a snapshot of all live keys, most recently used first.
+*/
+
+func (c *Cache) Keys() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := c.now()
+	keys := make([]string, 0, c.store.len())
+	c.store.forEach(func(item *Item) bool {
+		if !item.hidden(now) {
+			keys = append(keys, item.key)
+		}
+		return true
+	})
+	return keys
+}
+
+/*
+Range calls fn for every live entry, most recently used first.
+Iteration stops early if fn returns false.
+
+fn runs on a snapshot taken under the read lock, so it may call
+back into the cache (see CALLBACK CONTRACT).
+*/
+
+func (c *Cache) Range(fn func(key string, value interface{}) bool) {
+	c.mu.RLock()
+	now := c.now()
+	entries := make([]rangeEntry, 0, c.store.len())
+	c.store.forEach(func(item *Item) bool {
+		if !item.hidden(now) {
+			entries = append(entries, rangeEntry{item.key, item.value})
+		}
+		return true
+	})
+	c.mu.RUnlock()
+
+	for _, e := range entries {
+		if !fn(e.key, e.value) {
+			return
+		}
+	}
+}
+
+type rangeEntry struct {
+	key   string
+	value interface{}
+}
+
+/*
+All returns an iterator over live entries for use with range-over-func:
+
+    for key, value := range cache.All() {
+        ...
+    }
+
+Like Range, the loop body runs on a snapshot without holding the lock.
+*/
+
+func (c *Cache) All() iter.Seq2[string, interface{}] {
+	return func(yield func(string, interface{}) bool) {
+		c.Range(yield)
+	}
+}
@@ -29,6 +29,15 @@ STRUCTURE FIELDS
 key        -> Stored key reference (used during eviction removal)
 value      -> Actual user data (generic via interface{})
 expiration -> Expiration timestamp in Unix nanoseconds (int64)
+ttl        -> Most recently applied TTL (used by Touch for sliding expiry)
+cost       -> Entry weight used for Stats.Cost accounting
+version    -> Write version used as a compare-and-swap token
+access     -> Optional per-key hit counters (see WithAccessTracking)
+ns, gen    -> Owning namespace and its generation at write time
+tags       -> Invalidation tags (indexed in Cache.tags)
+negative   -> Marks a known-missing key (see negative.go)
+prev, next -> LRU links (listStore only)
+slot       -> Position in the entries slice (slabStore only)
 
 ================================================================================
 EXPIRATION MODEL
@@ -73,8 +82,18 @@ ensuring single-responsibility separation.
 
 type Item struct {
 	key        string
-	value      interface{} //Atomic unit of storage in cache.
-	expiration int64       //stored UnixNano Meaning: Number of nanoseconds since January 1, 1970 UTC (Unix epoch).
+	value      interface{}   //Atomic unit of storage in cache.
+	expiration int64         //stored UnixNano Meaning: Number of nanoseconds since January 1, 1970 UTC (Unix epoch).
+	ttl        time.Duration //last TTL applied, re-armed by Touch (0 = no expiration).
+	cost       int64         //weight of the entry as reported by the cost function.
+	version    uint64        //CAS token, refreshed on every write to this key.
+	access     *keyAccess    //per-key access statistics, nil unless tracking is enabled.
+	ns         *nsState      //owning namespace, nil for keys written outside a Namespace.
+	gen        uint64        //namespace generation the entry was written in.
+	tags       []string      //invalidation tags attached via SetWithTags.
+	negative   bool          //known-missing marker set by SetNotFound (value is nil).
+	prev, next *Item         //LRU neighbours (listStore only).
+	slot       uint32        //position in the entries slice (slabStore only).
 }
 
 /*
@@ -114,11 +133,17 @@ LOGIC FLOW
    - Compare current UnixNano timestamp with stored expiration.
    - If current time exceeds expiration → expired.
 
+3. Namespace invalidation:
+   - An entry written through a Namespace whose generation has since
+     been bumped (InvalidateNamespace) is reported as expired, so it
+     is hidden and reclaimed by the regular expiration machinery.
+
 ================================================================================
 USAGE CONTEXT
 ================================================================================
 
-This method is used in:
+Expired always uses the wall clock. Internally the cache calls
+expiredAt with the time of its configured Clock, used in:
 
 - Lazy expiration (checked during Get())
 - Active expiration (checked by janitor process)
@@ -129,7 +154,7 @@ RECEIVER DESIGN DECISION
 
 Pointer receiver (*Item) is used because:
 
-- The struct is stored inside a linked list element.
+- The struct is linked into the LRU list (or stored in a slab).
 - Avoids unnecessary copying.
 - Maintains consistent reference semantics.
 
@@ -139,8 +164,23 @@ within LRU-linked structures.
 */
 
 func (i *Item) Expired() bool {
+	return i.expiredAt(time.Now().UnixNano())
+}
+
+/*
+expiredAt is Expired evaluated against an explicit timestamp.
+
+The cache always calls this form with the time of its configured
+Clock (see WithClock), so injected clocks drive expiration. Loops
+read the clock once and reuse the value for every entry.
+*/
+
+func (i *Item) expiredAt(now int64) bool {
+	if i.ns != nil && i.gen != i.ns.gen.Load() {
+		return true
+	}
 	if i.expiration == 0 {
 		return false
 	}
-	return time.Now().UnixNano() > i.expiration
+	return now > i.expiration
 }
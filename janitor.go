@@ -34,6 +34,8 @@ EXECUTION MODEL
     → A dedicated goroutine is launched.
     → On each tick:
           deleteExpired() is executed.
+          The sweep duration and number of removed entries are
+          reported to the sweep hook (if configured), outside the lock.
 
 The goroutine runs independently of caller threads
 and operates asynchronously.
@@ -79,13 +81,28 @@ func (c *Cache) startJanitor() {
 		return
 	}
 
-	ticker := time.NewTicker(c.interval)
+	ticker := c.clock.NewTicker(c.interval)
+	j := &janitor{
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	c.janitor = j
+	c.workers.Add(1)
 
 	go func() {
+		defer c.workers.Done()
+		defer close(j.done)
 		for {
 			select {
-			case <-ticker.C:
-				c.deleteExpired()
+			case <-ticker.C():
+				start := c.clock.Now()
+				removed := c.deleteExpired()
+				if c.sweepHook != nil {
+					c.sweepHook(c.clock.Now().Sub(start), removed)
+				}
+			case <-j.stop:
+				ticker.Stop()
+				return
 			case <-c.stopChan:
 				ticker.Stop() //You stop the ticker before returning , because ticker leaks resources if not stopped.
 				return
@@ -95,45 +112,90 @@ func (c *Cache) startJanitor() {
 }
 
 /*
-Stop gracefully terminates the background janitor goroutine.
+janitor is the handle of one running janitor goroutine.
 
-================================================================================
-SHUTDOWN MECHANISM
-================================================================================
+stop -> Closed to ask this particular goroutine to exit
+done -> Closed by the goroutine once it has exited
+
+Unlike the cache-wide stopChan, a janitor handle is replaced every
+time the cleanup interval changes.
+*/
+
+type janitor struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+/*
+stopJanitor signals the current janitor (if any) and waits until it
+has fully exited, so no sweep can still be running afterwards.
 
-- Closing stopChan signals the janitor to exit.
-- The goroutine responds by:
-    1. Stopping the ticker.
-    2. Returning cleanly.
+NOTE:
+The caller must hold janitorMu and must NOT hold c.mu: a sweep in
+progress needs c.mu to finish.
+*/
 
-This prevents:
+func (c *Cache) stopJanitor() {
+	if c.janitor == nil {
+		return
+	}
+	close(c.janitor.stop)
+	<-c.janitor.done
+	c.janitor = nil
+}
 
-- Goroutine leaks
-- Ticker resource leaks
-- Background CPU usage after cache disposal
+/*
+SetCleanupInterval changes the active expiration frequency at runtime.
 
 ================================================================================
-USAGE CONTRACT
+BEHAVIOR
 ================================================================================
 
-Stop should be called once per Cache lifecycle.
-
-IMPORTANT:
-Calling Stop multiple times will cause a panic,
-since closing an already closed channel is illegal in Go.
+1. The running janitor (if any) is stopped, and this call waits for
+   it to exit — including any sweep already in progress.
+2. The new interval is stored.
+3. If d > 0, a fresh janitor is started with the new interval.
+   If d <= 0, active expiration stays disabled.
 
 ================================================================================
-WHY THIS MATTERS
+CONCURRENCY
 ================================================================================
 
-In long-running applications (e.g., HTTP servers,
-microservices, background workers), failing to stop
-background routines can lead to subtle resource leaks.
+Restarts are serialized by janitorMu, so concurrent calls can never
+leave two janitors running. Regular cache operations are not
+blocked while the janitor is being replaced.
+
+Once the cache is closed, the call is a no-op.
+*/
+
+func (c *Cache) SetCleanupInterval(d time.Duration) {
+	c.janitorMu.Lock()
+	defer c.janitorMu.Unlock()
+
+	if c.closed {
+		return
+	}
+
+	c.stopJanitor()
+	c.interval = d
+	c.startJanitor()
+}
 
-This method enables safe integration into
-production-grade systems.
+/*
+Stop terminates the background cleanup goroutine (if configured) and
+waits until it has exited, including any sweep in progress.
+
+Only the janitor is affected: stored entries, lazy expiration and
+every other operation keep working, and SetCleanupInterval may start
+a new janitor later. Stop is idempotent (earlier versions panicked
+on a second call).
+
+To release the whole cache, use Close or Shutdown (see lifecycle.go).
 */
 
 func (c *Cache) Stop() {
-	close(c.stopChan)
+	c.janitorMu.Lock()
+	defer c.janitorMu.Unlock()
+
+	c.stopJanitor()
 }
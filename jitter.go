@@ -0,0 +1,102 @@
+package tempuscache
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+/*
+TTL jitter.
+
+================================================================================
+MOTIVATION
+================================================================================
+
+Entries bulk-loaded with the same TTL all expire at the same instant.
+They are reclaimed by the same janitor sweep and, worse, every client
+misses at once and stampedes the backend to reload them.
+
+Jitter spreads expirations out by shortening each TTL by a random
+amount:
+
+    cache := tempuscache.New(tempuscache.WithTTLJitter(0.1))
+
+    cache.Set("a", v, time.Hour)   // expires after 54–60 minutes
+
+================================================================================
+SEMANTICS
+================================================================================
+
+With fraction f, a positive ttl becomes a uniformly random duration
+in (ttl·(1-f), ttl]. Jitter only ever SHORTENS a TTL, so no entry
+outlives the freshness bound the caller asked for.
+
+- Applies to every write with ttl > 0 (Set, SetMany, SetIfAbsent,
+  Namespace.Set, SetWithTags, new counters, ...).
+- ttl <= 0 (no expiration, or "keep current" on update) is never
+  jittered. TTLs supplied by WithDefaultTTL or clamped by WithMaxTTL
+  are jittered like explicit ones.
+- Expire, ExpireAt and Persist set explicit deadlines and are never
+  jittered.
+- The jittered TTL is what TTL() reports and what Touch re-arms.
+- SetWithJitter overrides the fraction for a single call.
+
+================================================================================
+DETERMINISM
+================================================================================
+
+By default the package-level math/rand/v2 generator is used. Tests can
+inject a seeded source with WithRandSource to get reproducible TTLs.
+The source is only used under the exclusive cache lock, so it does
+not need to be safe for concurrent use.
+*/
+
+/*
+SetWithJitter behaves like Set but applies the given jitter fraction
+instead of the cache default. A fraction of 0 stores ttl exactly.
+Fractions are clamped to [0, 1].
+*/
+
+func (c *Cache) SetWithJitter(key string, value interface{}, ttl time.Duration, fraction float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.setExact(key, value, c.jitterTTL(c.resolveTTL(ttl), clampFraction(fraction)))
+}
+
+/*
+jitterTTL shortens ttl by a random share of at most fraction.
+
+NOTE:
+The caller must hold the exclusive lock (the random source is not
+required to be concurrency-safe).
+*/
+
+func (c *Cache) jitterTTL(ttl time.Duration, fraction float64) time.Duration {
+	if ttl <= 0 || fraction <= 0 {
+		return ttl
+	}
+
+	var r float64
+	if c.rng != nil {
+		r = c.rng.Float64()
+	} else {
+		r = rand.Float64()
+	}
+
+	jittered := ttl - time.Duration(r*fraction*float64(ttl))
+	if jittered < 1 {
+		jittered = 1
+	}
+	return jittered
+}
+
+func clampFraction(f float64) float64 {
+	if f < 0 {
+		return 0
+	}
+	if f > 1 {
+		return 1
+	}
+	return f
+}
@@ -0,0 +1,186 @@
+package tempuscache
+
+import "strings"
+
+/*
+radixTree is an ordered, prefix-compressed index of cache keys.
+
+================================================================================
+ROLE IN ARCHITECTURE
+================================================================================
+
+The primary map gives O(1) point lookups but has no order and no
+notion of prefixes. When enabled via WithKeyIndex, every stored key
+is mirrored in this tree, which provides:
+
+- Lexicographically ordered traversal → resumable Scan cursors
+- Subtree selection by prefix         → DeleteByPrefix in O(k)
+
+================================================================================
+STRUCTURE
+================================================================================
+
+Each node holds the edge label leading to it from its parent.
+Concatenating the edges along a path yields a key; `leaf` marks paths
+that are actual keys. Chains of single-child non-leaf nodes are
+always merged, so the tree never holds more than 2n nodes.
+
+Children are kept sorted by the first byte of their edge, which makes
+an in-order walk (node, then children left to right) visit keys in
+lexicographic order.
+
+================================================================================
+CONCURRENCY
+================================================================================
+
+The tree has no locking of its own; it is only touched by Cache
+methods that already hold c.mu (Lock for mutation, RLock for walks).
+*/
+
+type radixTree struct {
+	root radixNode
+}
+
+type radixNode struct {
+	edge     string
+	children []*radixNode
+	leaf     bool
+}
+
+func (n *radixNode) child(b byte) (int, *radixNode) {
+	for i, c := range n.children {
+		if c.edge[0] == b {
+			return i, c
+		}
+	}
+	return -1, nil
+}
+
+func (n *radixNode) addChild(c *radixNode) {
+	i := 0
+	for i < len(n.children) && n.children[i].edge[0] < c.edge[0] {
+		i++
+	}
+	n.children = append(n.children, nil)
+	copy(n.children[i+1:], n.children[i:])
+	n.children[i] = c
+}
+
+func commonPrefixLen(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
+
+/*
+insert adds key to the tree. Inserting an existing key is a no-op.
+*/
+
+func (t *radixTree) insert(key string) {
+	n := &t.root
+	for {
+		if key == "" {
+			n.leaf = true
+			return
+		}
+
+		i, child := n.child(key[0])
+		if child == nil {
+			n.addChild(&radixNode{edge: key, leaf: true})
+			return
+		}
+
+		common := commonPrefixLen(key, child.edge)
+		if common < len(child.edge) {
+			split := &radixNode{edge: child.edge[:common]}
+			child.edge = child.edge[common:]
+			split.children = []*radixNode{child}
+			n.children[i] = split
+			child = split
+		}
+
+		key = key[common:]
+		n = child
+	}
+}
+
+/*
+remove deletes key from the tree, re-compressing the path.
+*/
+
+func (t *radixTree) remove(key string) {
+	if key == "" {
+		t.root.leaf = false
+		return
+	}
+	t.root.remove(key)
+}
+
+func (n *radixNode) remove(key string) bool {
+	i, child := n.child(key[0])
+	if child == nil || !strings.HasPrefix(key, child.edge) {
+		return false
+	}
+
+	rest := key[len(child.edge):]
+	if rest == "" {
+		if !child.leaf {
+			return false
+		}
+		child.leaf = false
+	} else if !child.remove(rest) {
+		return false
+	}
+
+	switch {
+	case child.leaf:
+	case len(child.children) == 0:
+		n.children = append(n.children[:i], n.children[i+1:]...)
+	case len(child.children) == 1:
+		merged := child.children[0]
+		merged.edge = child.edge + merged.edge
+		n.children[i] = merged
+	}
+	return true
+}
+
+/*
+walk visits, in lexicographic order, every key that starts with
+prefix and sorts strictly after `after`. It stops when fn returns
+false and reports whether the walk ran to completion.
+
+Subtrees that cannot contain qualifying keys are skipped without
+being visited.
+*/
+
+func (t *radixTree) walk(prefix, after string, fn func(key string) bool) bool {
+	return t.root.walk("", prefix, after, fn)
+}
+
+func (n *radixNode) walk(path, prefix, after string, fn func(string) bool) bool {
+	if n.leaf && path > after && strings.HasPrefix(path, prefix) {
+		if !fn(path) {
+			return false
+		}
+	}
+
+	for _, child := range n.children {
+		p := path + child.edge
+
+		if !strings.HasPrefix(p, prefix) && !strings.HasPrefix(prefix, p) {
+			continue
+		}
+
+		m := min(len(p), len(after))
+		if p[:m] < after[:m] {
+			continue
+		}
+
+		if !child.walk(p, prefix, after, fn) {
+			return false
+		}
+	}
+	return true
+}
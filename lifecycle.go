@@ -0,0 +1,127 @@
+package tempuscache
+
+import (
+	"context"
+	"io"
+)
+
+/*
+Cache lifecycle: Close and Shutdown.
+
+================================================================================
+MOTIVATION
+================================================================================
+
+The original Stop() closed stopChan unconditionally, so a second call
+panicked, and it returned before the janitor goroutine had actually
+exited — a sweep could still be running after Stop returned.
+
+Stop is now idempotent and waits for the janitor, but still only
+stops the janitor. Close and Shutdown release the whole cache with a
+well-defined contract.
+
+================================================================================
+SHUTDOWN SEQUENCE
+================================================================================
+
+1. The cache is marked closed (under both mu and janitorMu), so no
+   new operation or janitor restart can begin.
+2. All entries are dropped and their memory released. Gauges are
+   reset to zero. No removal events are published for them.
+3. Every event subscription is closed, ending consumers' range loops.
+4. stopChan is closed, signaling every background worker.
+5. The caller waits for all workers (tracked in c.workers) to exit.
+
+Steps 1–4 happen exactly once (closeOnce). Step 5 is performed by
+every caller, so concurrent Close calls all return only after the
+workers are gone.
+
+================================================================================
+BEHAVIOR AFTER CLOSE
+================================================================================
+
+- Operations returning an error (Incr, IncrBy, Decr, IncrByFloat)
+  return ErrClosed.
+- Lookups report "not found"; the cache is empty.
+- Writes are ignored; conditional writes (SetIfAbsent, ...) report
+  false.
+- Subscribe returns an already closed Subscription.
+- SetCleanupInterval is a no-op.
+
+Close implements io.Closer.
+*/
+
+var _ io.Closer = (*Cache)(nil)
+
+/*
+Close shuts the cache down and waits for all background workers
+to exit. It is idempotent and always returns nil.
+*/
+
+func (c *Cache) Close() error {
+	return c.Shutdown(context.Background())
+}
+
+/*
+Shutdown is Close with a deadline.
+
+RETURNS:
+- nil       → All background workers exited.
+- ctx.Err() → The context ended first. The cache is still closed
+              (no new work is accepted); remaining workers finish
+              on their own.
+*/
+
+func (c *Cache) Shutdown(ctx context.Context) error {
+	c.closeOnce.Do(c.markClosed)
+
+	done := make(chan struct{})
+	go func() {
+		c.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+/*
+Closed reports whether Close or Shutdown has been called.
+*/
+
+func (c *Cache) Closed() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.closed
+}
+
+func (c *Cache) markClosed() {
+	c.janitorMu.Lock()
+	defer c.janitorMu.Unlock()
+
+	c.mu.Lock()
+	c.closed = true
+
+	c.store.reset()
+	c.tags = nil
+	if c.index != nil {
+		c.index = &radixTree{}
+	}
+	c.stats.entries.Store(0)
+	c.stats.cost.Store(0)
+
+	for _, s := range c.subs {
+		s.closed = true
+		close(s.ch)
+	}
+	c.subs = nil
+	c.mu.Unlock()
+
+	close(c.stopChan)
+	c.janitor = nil
+}
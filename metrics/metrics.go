@@ -0,0 +1,388 @@
+/*
+Package metrics exports TempusCache statistics in the Prometheus
+text exposition format.
+
+================================================================================
+DESIGN
+================================================================================
+
+The package follows the semantics of a prometheus.Collector — every
+scrape takes a fresh, consistent snapshot of each tracked cache —
+without depending on the Prometheus client library. The output can
+be served directly over HTTP (Collector implements http.Handler) or
+written to any io.Writer, so it works with a live Prometheus server,
+a push gateway, or plain log files.
+
+================================================================================
+USAGE
+================================================================================
+
+	collector := metrics.NewCollector()
+
+	users := tempuscache.New(
+	    tempuscache.WithName("users"),
+	    tempuscache.WithCleanupInterval(time.Minute),
+	    collector.Track(),
+	)
+
+	http.Handle("/metrics", collector)
+
+================================================================================
+EXPORTED SERIES
+================================================================================
+
+All series carry a `cache` label holding the cache name (WithName).
+Label values must be unique within a scrape, so when several tracked
+caches share a name (including the empty default) the first keeps it
+and later ones are exported as "<name>#2", "<name>#3", ... A cache
+keeps the label it was first exported under for as long as it is
+tracked. Give every cache a distinct WithName to avoid this.
+
+tempuscache_hits_total                      counter
+tempuscache_misses_total                    counter
+tempuscache_evictions_total                 counter
+tempuscache_expirations_total               counter
+tempuscache_entries                         gauge
+tempuscache_janitor_sweep_duration_seconds  histogram
+tempuscache_sets_total                      counter
+tempuscache_replacements_total              counter
+tempuscache_deletes_total                   counter
+tempuscache_cost                            gauge
+
+Sweep durations are only recorded for caches with a cleanup
+interval, since only those run a janitor.
+
+================================================================================
+LIFETIME
+================================================================================
+
+A closed cache (Close / Shutdown) is dropped from the collector on
+the next Collect and its series disappear. Untrack removes a cache
+explicitly, e.g. one that is replaced without being closed.
+*/
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Krishna8167/tempuscache/v2"
+)
+
+/*
+SweepBuckets are the default upper bounds (in seconds) of the janitor
+sweep duration histogram. Sweeps are O(n) scans that usually complete
+in microseconds to milliseconds, so the buckets are exponential from
+10µs to 1s.
+*/
+
+var SweepBuckets = []float64{0.00001, 0.0001, 0.001, 0.01, 0.1, 1}
+
+/*
+Collector gathers metrics from every cache registered through Track.
+
+It is safe for concurrent use: scrapes may run while caches are
+being created and while janitors report sweeps.
+*/
+
+type Collector struct {
+	mu     sync.Mutex
+	caches []*tracked
+}
+
+type tracked struct {
+	cache   *tempuscache.Cache
+	sweeps  *histogram
+	label   string // `cache` label value, assigned on first Collect
+	labeled bool
+}
+
+/*
+NewCollector returns an empty Collector.
+*/
+
+func NewCollector() *Collector {
+	return &Collector{}
+}
+
+/*
+Track returns a tempuscache.Option that registers the cache being
+constructed with this collector and installs a sweep hook feeding
+the sweep duration histogram. Sweep hooks are additive, so Track
+can be combined with WithSweepHook in any order.
+*/
+
+func (col *Collector) Track() tempuscache.Option {
+	return func(c *tempuscache.Cache) {
+		t := &tracked{
+			cache:  c,
+			sweeps: newHistogram(SweepBuckets),
+		}
+
+		tempuscache.WithSweepHook(func(d time.Duration, _ int) {
+			t.sweeps.observe(d.Seconds())
+		})(c)
+
+		col.mu.Lock()
+		col.caches = append(col.caches, t)
+		col.mu.Unlock()
+	}
+}
+
+/*
+Untrack stops exporting c and releases the collector's reference to
+it. Untracking a cache that is not tracked is a no-op.
+*/
+
+func (col *Collector) Untrack(c *tempuscache.Cache) {
+	col.mu.Lock()
+	defer col.mu.Unlock()
+
+	col.caches = slices.DeleteFunc(col.caches, func(t *tracked) bool {
+		return t.cache == c
+	})
+}
+
+/*
+Label is a single name/value pair attached to a sample.
+*/
+
+type Label struct {
+	Name  string
+	Value string
+}
+
+/*
+Sample is one exported value of a metric family.
+
+Suffix is appended to the family name ("_bucket", "_sum", "_count"
+for histograms, empty otherwise).
+*/
+
+type Sample struct {
+	Suffix string
+	Labels []Label
+	Value  float64
+}
+
+/*
+Family groups all samples sharing a name, help text and type —
+the unit of the Prometheus exposition format.
+*/
+
+type Family struct {
+	Name    string
+	Help    string
+	Type    string
+	Samples []Sample
+}
+
+/*
+Collect takes a snapshot of every tracked cache.
+
+Families are returned in a fixed order; within a family, samples
+are ordered by cache registration order. Closed caches are untracked.
+*/
+
+func (col *Collector) Collect() []Family {
+	col.mu.Lock()
+	col.caches = slices.DeleteFunc(col.caches, func(t *tracked) bool {
+		return t.cache.Closed()
+	})
+	col.assignLabels()
+	caches := append([]*tracked(nil), col.caches...)
+	col.mu.Unlock()
+
+	families := []Family{
+		{Name: "tempuscache_hits_total", Help: "Total number of cache hits.", Type: "counter"},
+		{Name: "tempuscache_misses_total", Help: "Total number of cache misses.", Type: "counter"},
+		{Name: "tempuscache_evictions_total", Help: "Total number of entries evicted due to capacity limits.", Type: "counter"},
+		{Name: "tempuscache_expirations_total", Help: "Total number of entries removed after their TTL elapsed.", Type: "counter"},
+		{Name: "tempuscache_entries", Help: "Current number of stored entries.", Type: "gauge"},
+		{Name: "tempuscache_janitor_sweep_duration_seconds", Help: "Duration of background expiration sweeps.", Type: "histogram"},
+		{Name: "tempuscache_sets_total", Help: "Total number of successful writes.", Type: "counter"},
+		{Name: "tempuscache_replacements_total", Help: "Total number of writes that overwrote a live entry.", Type: "counter"},
+		{Name: "tempuscache_deletes_total", Help: "Total number of explicitly deleted entries.", Type: "counter"},
+		{Name: "tempuscache_cost", Help: "Current total cost of stored entries.", Type: "gauge"},
+	}
+
+	for _, t := range caches {
+		labels := []Label{{Name: "cache", Value: t.label}}
+		stats := t.cache.Stats()
+
+		families[0].Samples = append(families[0].Samples, Sample{Labels: labels, Value: float64(stats.Hits)})
+		families[1].Samples = append(families[1].Samples, Sample{Labels: labels, Value: float64(stats.Misses)})
+		families[2].Samples = append(families[2].Samples, Sample{Labels: labels, Value: float64(stats.Evictions)})
+		families[3].Samples = append(families[3].Samples, Sample{Labels: labels, Value: float64(stats.Expirations)})
+		families[4].Samples = append(families[4].Samples, Sample{Labels: labels, Value: float64(stats.Entries)})
+		families[5].Samples = append(families[5].Samples, t.sweeps.samples(labels)...)
+		families[6].Samples = append(families[6].Samples, Sample{Labels: labels, Value: float64(stats.Sets)})
+		families[7].Samples = append(families[7].Samples, Sample{Labels: labels, Value: float64(stats.Replacements)})
+		families[8].Samples = append(families[8].Samples, Sample{Labels: labels, Value: float64(stats.Deletes)})
+		families[9].Samples = append(families[9].Samples, Sample{Labels: labels, Value: float64(stats.Cost)})
+	}
+
+	return families
+}
+
+/*
+assignLabels gives every newly tracked cache a `cache` label value
+not used by any other tracked cache (see EXPORTED SERIES).
+
+NOTE:
+The caller must hold col.mu.
+*/
+
+func (col *Collector) assignLabels() {
+	used := make(map[string]bool, len(col.caches))
+	for _, t := range col.caches {
+		if t.labeled {
+			used[t.label] = true
+		}
+	}
+
+	for _, t := range col.caches {
+		if t.labeled {
+			continue
+		}
+		name := t.cache.Name()
+		label := name
+		for n := 2; used[label]; n++ {
+			label = name + "#" + strconv.Itoa(n)
+		}
+		t.label, t.labeled = label, true
+		used[label] = true
+	}
+}
+
+/*
+WriteTo renders the current snapshot in the Prometheus text
+exposition format (version 0.0.4).
+*/
+
+func (col *Collector) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+
+	for _, f := range col.Collect() {
+		if len(f.Samples) == 0 {
+			continue
+		}
+		fmt.Fprintf(&buf, "# HELP %s %s\n", f.Name, f.Help)
+		fmt.Fprintf(&buf, "# TYPE %s %s\n", f.Name, f.Type)
+		for _, s := range f.Samples {
+			buf.WriteString(f.Name)
+			buf.WriteString(s.Suffix)
+			writeLabels(&buf, s.Labels)
+			buf.WriteByte(' ')
+			buf.WriteString(formatValue(s.Value))
+			buf.WriteByte('\n')
+		}
+	}
+
+	return buf.WriteTo(w)
+}
+
+/*
+ServeHTTP exposes the snapshot as a Prometheus scrape endpoint.
+*/
+
+func (col *Collector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	col.WriteTo(w)
+}
+
+func writeLabels(buf *bytes.Buffer, labels []Label) {
+	if len(labels) == 0 {
+		return
+	}
+
+	buf.WriteByte('{')
+	for i, l := range labels {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(l.Name)
+		buf.WriteString(`="`)
+		buf.WriteString(labelEscaper.Replace(l.Value))
+		buf.WriteByte('"')
+	}
+	buf.WriteByte('}')
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+/*
+histogram is a minimal fixed-bucket histogram with Prometheus
+(cumulative) bucket semantics.
+*/
+
+type histogram struct {
+	mu     sync.Mutex
+	bounds []float64
+	counts []uint64 // per-bucket, non-cumulative; last slot is +Inf
+	sum    float64
+	count  uint64
+}
+
+func newHistogram(bounds []float64) *histogram {
+	b := append([]float64(nil), bounds...)
+	sort.Float64s(b)
+	return &histogram{
+		bounds: b,
+		counts: make([]uint64, len(b)+1),
+	}
+}
+
+func (h *histogram) observe(v float64) {
+	i := sort.SearchFloat64s(h.bounds, v)
+
+	h.mu.Lock()
+	h.counts[i]++
+	h.sum += v
+	h.count++
+	h.mu.Unlock()
+}
+
+func (h *histogram) samples(labels []Label) []Sample {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	out := make([]Sample, 0, len(h.counts)+2)
+	var cumulative uint64
+	for i, n := range h.counts {
+		cumulative += n
+		le := math.Inf(1)
+		if i < len(h.bounds) {
+			le = h.bounds[i]
+		}
+		bucketLabels := append(append([]Label(nil), labels...), Label{Name: "le", Value: formatValue(le)})
+		out = append(out, Sample{Suffix: "_bucket", Labels: bucketLabels, Value: float64(cumulative)})
+	}
+
+	out = append(out,
+		Sample{Suffix: "_sum", Labels: labels, Value: h.sum},
+		Sample{Suffix: "_count", Labels: labels, Value: float64(h.count)},
+	)
+	return out
+}
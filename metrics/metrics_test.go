@@ -0,0 +1,144 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Krishna8167/tempuscache/v2"
+	"github.com/Krishna8167/tempuscache/v2/tempuscachetest"
+)
+
+/*
+TestExposition verifies that tracked cache statistics are rendered
+in the Prometheus text format with the cache name label.
+*/
+
+func TestExposition(t *testing.T) {
+	collector := NewCollector()
+
+	cache := tempuscache.New(
+		tempuscache.WithName("users"),
+		collector.Track(),
+	)
+
+	cache.Set("a", 1, 0)
+	cache.Get("a")
+	cache.Get("b")
+
+	var buf bytes.Buffer
+	if _, err := collector.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"# TYPE tempuscache_hits_total counter\n",
+		`tempuscache_hits_total{cache="users"} 1` + "\n",
+		`tempuscache_misses_total{cache="users"} 1` + "\n",
+		`tempuscache_entries{cache="users"} 1` + "\n",
+		`tempuscache_sets_total{cache="users"} 1` + "\n",
+		`tempuscache_janitor_sweep_duration_seconds_bucket{cache="users",le="+Inf"} 0` + "\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestSweepHistogram(t *testing.T) {
+	collector := NewCollector()
+	clock := tempuscachetest.NewClock(time.Unix(1_700_000_000, 0))
+	var userSweeps atomic.Int32
+
+	cache := tempuscache.New(
+		tempuscache.WithClock(clock),
+		tempuscache.WithName(`we"ird`),
+		tempuscache.WithCleanupInterval(time.Millisecond),
+		tempuscache.WithSweepHook(func(time.Duration, int) { userSweeps.Add(1) }),
+		collector.Track(),
+	)
+	defer cache.Stop()
+
+	cache.Set("a", 1, time.Millisecond)
+	clock.Advance(2 * time.Millisecond)
+	// The second tick is only accepted once the first sweep has finished.
+	clock.Advance(time.Millisecond)
+
+	rec := httptest.NewRecorder()
+	collector.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	out := rec.Body.String()
+
+	if !strings.Contains(out, `tempuscache_expirations_total{cache="we\"ird"} 1`) {
+		t.Fatalf("expected janitor expiration with escaped label, got:\n%s", out)
+	}
+
+	if strings.Contains(out, `tempuscache_janitor_sweep_duration_seconds_count{cache="we\"ird"} 0`) {
+		t.Fatalf("expected recorded sweeps, got:\n%s", out)
+	}
+	if userSweeps.Load() == 0 {
+		t.Fatal("expected the user's sweep hook to keep running alongside Track")
+	}
+}
+
+/*
+TestUntrack verifies that untracked and closed caches stop being
+exported.
+*/
+
+func TestUntrack(t *testing.T) {
+	collector := NewCollector()
+
+	users := tempuscache.New(tempuscache.WithName("users"), collector.Track())
+	sessions := tempuscache.New(tempuscache.WithName("sessions"), collector.Track())
+	orders := tempuscache.New(tempuscache.WithName("orders"), collector.Track())
+
+	collector.Untrack(users)
+	sessions.Close()
+
+	entries := collector.Collect()[4].Samples
+	if len(entries) != 1 || entries[0].Labels[0].Value != "orders" {
+		t.Fatalf("expected only orders to be exported, got %v", entries)
+	}
+
+	collector.Untrack(orders)
+	collector.Untrack(orders)
+	if n := len(collector.Collect()[4].Samples); n != 0 {
+		t.Fatalf("expected no caches after untracking, got %d", n)
+	}
+}
+
+/*
+TestDuplicateNames verifies that caches sharing a name, including
+the empty default, are exported under distinct, stable labels.
+*/
+
+func TestDuplicateNames(t *testing.T) {
+	collector := NewCollector()
+
+	first := tempuscache.New(tempuscache.WithName("users"), collector.Track())
+	tempuscache.New(tempuscache.WithName("users"), collector.Track())
+	tempuscache.New(collector.Track())
+	tempuscache.New(collector.Track())
+
+	labels := func() []string {
+		var out []string
+		for _, s := range collector.Collect()[4].Samples {
+			out = append(out, s.Labels[0].Value)
+		}
+		return out
+	}
+
+	if got := strings.Join(labels(), ","); got != "users,users#2,,#2" {
+		t.Fatalf("expected distinct labels, got %q", got)
+	}
+
+	first.Close()
+	tempuscache.New(tempuscache.WithName("users"), collector.Track())
+	if got := strings.Join(labels(), ","); got != "users#2,,#2,users" {
+		t.Fatalf("expected existing labels to stay stable, got %q", got)
+	}
+}
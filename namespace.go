@@ -0,0 +1,256 @@
+package tempuscache
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+/*
+Key namespaces with O(1) bulk invalidation.
+
+================================================================================
+MOTIVATION
+================================================================================
+
+Subsystems sharing one Cache typically prefix keys by hand
+("user:", "org:"), and dropping everything for one subsystem means
+scanning the whole data map. A Namespace is a scoped view:
+
+    users := cache.Namespace("user:", 10*time.Minute)
+
+    users.Set("42", profile, 0)   // stored as "user:42", 10 minute TTL
+    users.Get("42")
+
+    users.Invalidate()            // O(1), regardless of size
+
+================================================================================
+GENERATION COUNTERS
+================================================================================
+
+Each namespace has a generation number. Every entry written through
+the namespace records the generation that was current at write time
+(Item.gen).
+
+Invalidate simply increments the generation. From that instant,
+Item.Expired() reports every older entry as expired, so they are:
+
+- Hidden from Get, Peek, Has, Keys, Range, ... (including the plain
+  Cache API using the full prefixed key)
+- Reclaimed lazily on access, or actively by the janitor, exactly
+  like TTL-expired entries (and counted as expirations)
+
+Invalidation therefore costs O(1); memory is reclaimed incrementally
+by the existing expiration machinery (or by LRU eviction).
+
+================================================================================
+SHARED STATE
+================================================================================
+
+Calling Namespace twice with the same prefix returns views sharing
+one generation counter and one set of statistics. Each view keeps
+its own default TTL.
+
+================================================================================
+OWNERSHIP
+================================================================================
+
+An entry belongs to the namespace that wrote it LAST:
+
+- A plain Cache write (Set, Incr, CompareAndSwap, ...) to the full key
+  detaches it; the namespace no longer counts or invalidates it.
+- With overlapping prefixes ("user:" and "user:admin:") a write
+  through the other namespace moves the entry there.
+
+Either way the previous owner's Entries gauge is decremented.
+*/
+
+/*
+nsState is the per-prefix state shared by all views of a namespace.
+*/
+
+type nsState struct {
+	prefix string
+	gen    atomic.Uint64
+	stats  counters
+}
+
+/*
+Namespace is a prefix-scoped view of a Cache.
+*/
+
+type Namespace struct {
+	cache      *Cache
+	state      *nsState
+	defaultTTL time.Duration
+}
+
+/*
+Namespace returns a view that transparently prefixes every key.
+
+PARAMETERS:
+- prefix     : Prepended to every key (include your own separator)
+- defaultTTL : Applied when Namespace.Set is called with ttl == 0.
+               A defaultTTL of 0 keeps the Set semantics of the
+               underlying cache (no expiration).
+*/
+
+func (c *Cache) Namespace(prefix string, defaultTTL time.Duration) *Namespace {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.namespaces == nil {
+		c.namespaces = make(map[string]*nsState)
+	}
+
+	state, found := c.namespaces[prefix]
+	if !found {
+		state = &nsState{prefix: prefix}
+		c.namespaces[prefix] = state
+	}
+
+	return &Namespace{
+		cache:      c,
+		state:      state,
+		defaultTTL: defaultTTL,
+	}
+}
+
+/*
+InvalidateNamespace drops every entry written through Namespace(prefix)
+in O(1). Unknown prefixes are ignored.
+
+Keys written with the plain Cache API are NOT affected, even if they
+share the prefix, and neither are namespace keys last overwritten
+through the plain API (see OWNERSHIP).
+*/
+
+func (c *Cache) InvalidateNamespace(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if state, found := c.namespaces[prefix]; found {
+		state.invalidate()
+	}
+}
+
+func (s *nsState) invalidate() {
+	s.gen.Add(1)
+	s.stats.entries.Store(0)
+}
+
+/*
+Prefix returns the namespace prefix.
+*/
+
+func (n *Namespace) Prefix() string {
+	return n.state.prefix
+}
+
+/*
+Set stores value under prefix+key.
+
+ttl == 0 applies the namespace default TTL.
+*/
+
+func (n *Namespace) Set(key string, value interface{}, ttl time.Duration) {
+	if ttl == 0 {
+		ttl = n.defaultTTL
+	}
+
+	c := n.cache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return
+	}
+
+	// set detaches the item from any previous owner, including this
+	// namespace, so it is always (re)attached here.
+	item := c.set(n.state.prefix+key, value, ttl)
+	n.state.stats.sets.Add(1)
+
+	item.ns = n.state
+	item.gen = n.state.gen.Load()
+	n.state.stats.entries.Add(1)
+}
+
+/*
+detachNamespace removes item from its owning namespace, decrementing
+that namespace's Entries gauge unless the entry was already
+invalidated.
+
+NOTE:
+The caller must hold the exclusive lock.
+*/
+
+func detachNamespace(item *Item) {
+	if item.ns == nil {
+		return
+	}
+	if item.gen == item.ns.gen.Load() {
+		item.ns.stats.entries.Add(-1)
+	}
+	item.ns, item.gen = nil, 0
+}
+
+/*
+Get retrieves prefix+key, counting the lookup in both the cache
+and the namespace statistics.
+*/
+
+func (n *Namespace) Get(key string) (interface{}, bool) {
+	c := n.cache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	value, found := c.get(n.state.prefix + key)
+	if found {
+		n.state.stats.hits.Add(1)
+	} else {
+		n.state.stats.misses.Add(1)
+	}
+	return value, found
+}
+
+/*
+Delete removes prefix+key.
+*/
+
+func (n *Namespace) Delete(key string) {
+	c := n.cache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.delete(n.state.prefix + key) {
+		n.state.stats.deletes.Add(1)
+	}
+}
+
+/*
+Invalidate drops every entry of this namespace in O(1).
+See InvalidateNamespace.
+*/
+
+func (n *Namespace) Invalidate() {
+	n.cache.InvalidateNamespace(n.state.prefix)
+}
+
+/*
+Stats returns the namespace's own statistics.
+
+Tracked fields: Hits, Misses, Sets, Deletes and Entries (entries
+written through the namespace in the current generation). The
+remaining fields are zero; cache-wide figures are in Cache.Stats.
+*/
+
+func (n *Namespace) Stats() Stats {
+	s := &n.state.stats
+	return Stats{
+		Hits:    s.hits.Load(),
+		Misses:  s.misses.Load(),
+		Sets:    s.sets.Load(),
+		Deletes: s.deletes.Load(),
+		Entries: s.entries.Load(),
+	}
+}
@@ -0,0 +1,174 @@
+package tempuscache
+
+import "time"
+
+/*
+Negative caching for known-missing keys.
+
+================================================================================
+MOTIVATION
+================================================================================
+
+A plain miss stores nothing, so every lookup of a key that does not
+exist in the backing store (an unknown user id, a deleted product)
+goes all the way to the database. Under enumeration or retry storms
+those lookups dominate the load.
+
+SetNotFound records "this key is known to be missing" for a TTL:
+
+    value, status := cache.GetWithStatus(key)
+    switch status {
+    case tempuscache.StatusHit:
+        return value, nil
+    case tempuscache.StatusNotFound:
+        return nil, ErrNoSuchUser          // answered from the cache
+    }
+
+    user, err := db.LoadUser(key)
+    if errors.Is(err, sql.ErrNoRows) {
+        cache.SetNotFound(key, 30*time.Second)
+        ...
+    }
+
+================================================================================
+SEMANTICS
+================================================================================
+
+A negative entry is a regular entry without a value. It occupies a
+slot, counts towards Stats.Entries, expires, is evicted and is
+deleted exactly like any other entry, and Set on the same key
+replaces it (starting a fresh expiration).
+
+Having no value, a negative entry is never passed to the WithCost
+function; it always costs negativeCost.
+
+For everything that reads VALUES it is absent:
+
+- Get, GetMany and GetWithVersion report not found.
+- Peek, Has, Len, Keys, Range, All and Scan skip it.
+- Conditional writes, counters and the TTL operations treat the key
+  as missing.
+
+Only GetWithStatus tells the two kinds of "not found" apart.
+
+================================================================================
+STATISTICS
+================================================================================
+
+- NegativeSets → SetNotFound calls (also counted in Sets)
+- NegativeHits → Lookups answered by a negative entry
+
+Negative hits are counted in neither Hits nor Misses, so HitRatio
+keeps describing the value cache alone.
+*/
+
+/*
+negativeCost is the fixed cost of a negative entry.
+*/
+
+const negativeCost = 1
+
+/*
+Status is the outcome of GetWithStatus.
+*/
+
+type Status uint8
+
+const (
+	StatusMiss     Status = iota // nothing cached; consult the source
+	StatusHit                    // a value is cached
+	StatusNotFound               // the key is cached as known-missing
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusMiss:
+		return "miss"
+	case StatusHit:
+		return "hit"
+	case StatusNotFound:
+		return "not_found"
+	}
+	return "unknown"
+}
+
+/*
+SetNotFound caches key as known-missing for ttl.
+
+Any value currently stored under key is replaced. DefaultExpiration
+applies WithDefaultTTL if configured; otherwise ttl <= 0 stores a
+negative entry that never expires (subject to WithMaxTTL). Prefer a
+finite TTL so keys that are created later become visible.
+*/
+
+func (c *Cache) SetNotFound(key string, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if ttl < 0 || (ttl == DefaultExpiration && c.defaultTTL == 0) {
+		ttl = NoExpiration
+	}
+
+	item := c.write(key, nil, negativeCost, c.jitterTTL(c.resolveTTL(ttl), c.jitter))
+	if item == nil {
+		return
+	}
+	item.negative = true
+	c.stats.negativeSets.Add(1)
+}
+
+/*
+GetWithStatus behaves like Get but distinguishes a regular miss from
+a negative entry.
+
+RETURNS:
+- (value, StatusHit)    -> Key holds a live value
+- (nil, StatusNotFound) -> Key is cached as known-missing
+- (nil, StatusMiss)     -> Key is absent or expired
+*/
+
+func (c *Cache) GetWithStatus(key string) (interface{}, Status) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, found := c.lookup(key)
+	if !found {
+		c.stats.misses.Add(1)
+		return nil, StatusMiss
+	}
+
+	c.store.moveToFront(item)
+	if item.negative {
+		c.stats.negativeHits.Add(1)
+		return nil, StatusNotFound
+	}
+
+	c.stats.hits.Add(1)
+	c.recordAccess(item)
+	return item.value, StatusHit
+}
+
+/*
+lookupValue is lookup restricted to entries holding a value:
+negative entries are reported as absent (but left in place).
+
+NOTE:
+The caller must hold the exclusive lock.
+*/
+
+func (c *Cache) lookupValue(key string) (*Item, bool) {
+	item, found := c.lookup(key)
+	if !found || item.negative {
+		return nil, false
+	}
+	return item, true
+}
+
+/*
+hidden reports whether read-only inspection must skip the item:
+it has expired or is a negative entry.
+*/
+
+func (i *Item) hidden(now int64) bool {
+	return i.negative || i.expiredAt(now)
+}
@@ -1,6 +1,7 @@
 package tempuscache
 
 import (
+	"math/rand/v2"
 	"time"
 )
 
@@ -175,3 +176,281 @@ func WithMaxEntries(n int) Option {
 		c.maxEntries = n
 	}
 }
+
+/*
+WithName assigns a human-readable identifier to the cache.
+
+================================================================================
+PURPOSE
+================================================================================
+
+Applications frequently run several caches side by side
+(users, sessions, rate limits, ...). The name distinguishes
+them in observability tooling; the metrics subpackage, for
+example, exports it as the `cache` label on every series.
+
+The name has no effect on cache behavior.
+*/
+
+func WithName(name string) Option {
+	return func(c *Cache) {
+		c.name = name
+	}
+}
+
+/*
+WithSweepHook registers an observer invoked after every janitor sweep.
+
+================================================================================
+PARAMETER
+================================================================================
+
+fn(d, removed):
+    d       -> Wall-clock duration of the sweep (including lock wait)
+    removed -> Number of expired entries deleted by the sweep
+
+================================================================================
+BEHAVIOR
+================================================================================
+
+- The hook runs on the janitor goroutine, AFTER the lock is released,
+  so it may safely call back into the cache.
+- It is only invoked when a cleanup interval is configured.
+- A slow hook delays the next sweep; keep it lightweight.
+- Hooks are additive: every WithSweepHook option (including the one
+  installed by metrics.Collector.Track) runs, in the order given.
+  A nil fn is ignored.
+
+Used by the metrics subpackage to build sweep duration histograms.
+*/
+
+func WithSweepHook(fn func(d time.Duration, removed int)) Option {
+	return func(c *Cache) {
+		if fn == nil {
+			return
+		}
+		if prev := c.sweepHook; prev != nil {
+			c.sweepHook = func(d time.Duration, removed int) {
+				prev(d, removed)
+				fn(d, removed)
+			}
+			return
+		}
+		c.sweepHook = fn
+	}
+}
+
+/*
+WithCost configures how the weight of each entry is measured.
+
+================================================================================
+PARAMETER
+================================================================================
+
+fn(key, value) int64:
+    Returns the cost of storing value under key — typically its
+    approximate size in bytes.
+
+================================================================================
+BEHAVIOR
+================================================================================
+
+- fn is evaluated on every write, under the cache lock; keep it cheap.
+- The running total is exposed as Stats.Cost.
+- Without this option every entry costs 1.
+
+Cost is currently used for accounting only; capacity is still
+enforced by entry count (WithMaxEntries).
+*/
+
+func WithCost(fn func(key string, value interface{}) int64) Option {
+	return func(c *Cache) {
+		c.costFn = fn
+	}
+}
+
+/*
+WithAccessTracking enables per-key hit counters and last-access
+timestamps, queried through TopKeys.
+
+See access.go for the cost model. Tracking is disabled by default.
+*/
+
+func WithAccessTracking() Option {
+	return func(c *Cache) {
+		if c.access == nil {
+			c.access = &accessConfig{}
+		}
+	}
+}
+
+/*
+WithHotKeyDetection enables access tracking and reports keys whose
+request rate exceeds a limit.
+
+================================================================================
+PARAMETERS
+================================================================================
+
+threshold (uint64):
+    Number of hits within one window that marks a key as hot.
+
+window (time.Duration):
+    Length of the fixed counting window (e.g. time.Second).
+
+fn(key, hits):
+    Invoked on its own goroutine, at most once per key per window,
+    when the key reaches threshold hits.
+
+================================================================================
+EXAMPLE
+================================================================================
+
+    cache := New(
+        WithHotKeyDetection(1000, time.Second, func(key string, hits uint64) {
+            log.Printf("hot key %q: %d hits/s", key, hits)
+        }),
+    )
+
+If threshold == 0, window <= 0 or fn == nil, only plain access
+tracking is enabled.
+*/
+
+func WithHotKeyDetection(threshold uint64, window time.Duration, fn func(key string, hits uint64)) Option {
+	return func(c *Cache) {
+		if threshold == 0 || window <= 0 {
+			fn = nil
+		}
+		c.access = &accessConfig{
+			threshold: threshold,
+			window:    window,
+			onHot:     fn,
+		}
+	}
+}
+
+/*
+WithKeyIndex maintains an ordered radix-tree index of all keys.
+
+================================================================================
+BENEFITS
+================================================================================
+
+- Scan requires it; without this option Scan returns no keys.
+- DeleteByPrefix runs in O(k) for k matching keys instead of O(n).
+
+================================================================================
+COST
+================================================================================
+
+- Extra memory for tree nodes (at most 2 per key).
+- Insert and removal become O(key length) instead of O(1).
+
+Enable it when prefix operations or full scans are frequent.
+*/
+
+func WithKeyIndex() Option {
+	return func(c *Cache) {
+		c.index = &radixTree{}
+	}
+}
+
+/*
+WithClock replaces the time source used for TTLs, expiration checks,
+access tracking, event timestamps and the janitor ticker.
+
+Intended primarily for tests; see the tempuscachetest package for a
+controllable fake clock. A nil clock is ignored.
+*/
+
+func WithClock(clock Clock) Option {
+	return func(c *Cache) {
+		if clock != nil {
+			c.clock = clock
+		}
+	}
+}
+
+/*
+WithCoarseClock enables coarse clock mode: expiration checks and TTL
+computation read a timestamp refreshed every resolution by a
+background goroutine instead of calling the clock on every operation.
+
+Entries may outlive their TTL by up to one resolution.
+See clock.go for details. A non-positive resolution is ignored.
+*/
+
+func WithCoarseClock(resolution time.Duration) Option {
+	return func(c *Cache) {
+		if resolution > 0 {
+			c.coarse = &coarseClock{resolution: resolution}
+		}
+	}
+}
+
+/*
+WithTTLJitter randomizes expirations to avoid synchronized expiry
+storms: every positive TTL is shortened by a random share of at most
+fraction (clamped to [0, 1]). See jitter.go.
+*/
+
+func WithTTLJitter(fraction float64) Option {
+	return func(c *Cache) {
+		c.jitter = clampFraction(fraction)
+	}
+}
+
+/*
+WithRandSource sets the random source used for TTL jitter, making
+jittered TTLs reproducible in tests. A nil source is ignored.
+*/
+
+func WithRandSource(src rand.Source) Option {
+	return func(c *Cache) {
+		if src != nil {
+			c.rng = rand.New(src)
+		}
+	}
+}
+
+/*
+WithDefaultTTL sets the TTL applied when a write passes
+DefaultExpiration (0). See ttlpolicy.go. A non-positive d is ignored.
+*/
+
+func WithDefaultTTL(d time.Duration) Option {
+	return func(c *Cache) {
+		if d > 0 {
+			c.defaultTTL = d
+		}
+	}
+}
+
+/*
+WithMaxTTL caps the lifetime of every entry at d, including entries
+written with NoExpiration. See ttlpolicy.go. A non-positive d is
+ignored.
+*/
+
+func WithMaxTTL(d time.Duration) Option {
+	return func(c *Cache) {
+		if d > 0 {
+			c.maxTTL = d
+		}
+	}
+}
+
+/*
+WithSlabStorage replaces the default map[string]*Item + linked list
+layout with a pointer-free index into a single slice
+of entries and an intrusive, index-based LRU list.
+
+The public API and all semantics are unchanged; the GC has far fewer
+objects to scan. See slab.go.
+*/
+
+func WithSlabStorage() Option {
+	return func(c *Cache) {
+		c.store = newSlabStore()
+	}
+}
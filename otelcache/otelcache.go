@@ -0,0 +1,342 @@
+/*
+Package otelcache adds optional OpenTelemetry instrumentation to a
+TempusCache instance.
+
+================================================================================
+DESIGN
+================================================================================
+
+The core tempuscache package has no third-party dependencies and no
+notion of context.Context. Instrumentation therefore lives in this
+separate package as a thin wrapper:
+
+	cache := tempuscache.New(tempuscache.WithName("users"))
+
+	traced, err := otelcache.New(cache,
+	    otelcache.WithTracerProvider(tp),
+	    otelcache.WithMeterProvider(mp),
+	)
+
+	value, found := traced.Get(ctx, "user:1")
+
+otelcache is currently part of the tempuscache module, so the
+OpenTelemetry requirements appear in the module graph of every
+application using tempuscache. Thanks to module graph pruning they
+are never downloaded or compiled unless otelcache is imported, but
+they do show up in `go list -m all`.
+
+otelcache will move to its own go.mod once a tagged tempuscache
+release contains every API it wraps (Name, GetMany, SetMany and the
+extended Stats). Until then a nested module could only build against
+a local replace directive, which Go ignores for dependencies.
+
+================================================================================
+TRACING
+================================================================================
+
+Every wrapped operation (Get, Set, Delete, GetMany, SetMany) runs
+inside a span named "tempuscache.<Operation>" carrying:
+
+- cache.name      → Cache name (WithName)
+- cache.key_hash  → Keyed hash of the key (raw keys may contain PII)
+- cache.hit       → Lookup outcome (Get only)
+- cache.ttl_ms    → Requested TTL in milliseconds (Set only)
+- cache.keys      → Batch size (GetMany / SetMany)
+- cache.hits      → Number of hits (GetMany)
+
+================================================================================
+METRICS
+================================================================================
+
+Asynchronous (observable) instruments mirror Stats and are read on
+each collection, so the hot path is not touched:
+
+tempuscache.hits, .misses, .sets, .deletes, .evictions,
+.expirations (counters) and tempuscache.entries, .cost (gauges),
+all carrying the cache.name attribute.
+
+Without WithTracerProvider / WithMeterProvider the global
+providers registered with the otel package are used.
+
+================================================================================
+KEY HASHING
+================================================================================
+
+Cache keys are often low-entropy ("user:12345"), so a plain hash
+could be reversed by enumerating candidates. cache.key_hash is
+therefore HMAC-SHA256 under a secret, truncated to 64 bits:
+
+  - By default each wrapper draws a random secret: hashes correlate
+    within that wrapper but are meaningless outside it.
+  - WithKeyHashSecret sets a shared secret, so hashes correlate across
+    instances. Anyone holding the secret can enumerate keys again;
+    treat it like a credential.
+*/
+package otelcache
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Krishna8167/tempuscache/v2"
+)
+
+const instrumentationName = "github.com/Krishna8167/tempuscache/v2/otelcache"
+
+/*
+Option configures the instrumentation wrapper.
+*/
+
+type Option func(*config)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	keySecret      []byte
+}
+
+/*
+WithTracerProvider sets the TracerProvider used to create spans.
+*/
+
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+/*
+WithMeterProvider sets the MeterProvider used to register the
+Stats-mirroring instruments.
+*/
+
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = mp
+	}
+}
+
+/*
+WithKeyHashSecret sets the HMAC secret used for cache.key_hash. Use
+the same secret on every instance whose spans should be correlated
+by key. An empty secret is ignored. See KEY HASHING above.
+*/
+
+func WithKeyHashSecret(secret []byte) Option {
+	return func(c *config) {
+		if len(secret) > 0 {
+			c.keySecret = append([]byte(nil), secret...)
+		}
+	}
+}
+
+/*
+Cache wraps a *tempuscache.Cache with tracing and metrics.
+
+Operations that are not wrapped remain available through Unwrap().
+*/
+
+type Cache struct {
+	cache        *tempuscache.Cache
+	tracer       trace.Tracer
+	keySecret    []byte
+	attrs        []attribute.KeyValue
+	registration metric.Registration
+}
+
+/*
+New instruments cache and registers its metric instruments.
+
+RETURNS:
+An error if the MeterProvider rejects an instrument or callback, or
+if no random key-hash secret could be generated.
+*/
+
+func New(cache *tempuscache.Cache, opts ...Option) (*Cache, error) {
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.keySecret == nil {
+		cfg.keySecret = make([]byte, 32)
+		if _, err := rand.Read(cfg.keySecret); err != nil {
+			return nil, err
+		}
+	}
+
+	c := &Cache{
+		cache:     cache,
+		tracer:    cfg.tracerProvider.Tracer(instrumentationName),
+		keySecret: cfg.keySecret,
+		attrs:     []attribute.KeyValue{attribute.String("cache.name", cache.Name())},
+	}
+
+	if err := c.registerMetrics(cfg.meterProvider.Meter(instrumentationName)); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+/*
+Unwrap returns the underlying cache.
+*/
+
+func (c *Cache) Unwrap() *tempuscache.Cache {
+	return c.cache
+}
+
+/*
+Close unregisters the metric callback. The underlying cache is
+left running; stop it separately.
+*/
+
+func (c *Cache) Close() error {
+	return c.registration.Unregister()
+}
+
+/*
+Get wraps tempuscache.Cache.Get in a span recording the key hash
+and hit/miss outcome.
+*/
+
+func (c *Cache) Get(ctx context.Context, key string) (interface{}, bool) {
+	_, span := c.start(ctx, "tempuscache.Get", attribute.String("cache.key_hash", c.hashKey(key)))
+	defer span.End()
+
+	value, found := c.cache.Get(key)
+	span.SetAttributes(attribute.Bool("cache.hit", found))
+	return value, found
+}
+
+/*
+Set wraps tempuscache.Cache.Set in a span recording the key hash and TTL.
+*/
+
+func (c *Cache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) {
+	_, span := c.start(ctx, "tempuscache.Set",
+		attribute.String("cache.key_hash", c.hashKey(key)),
+		attribute.Int64("cache.ttl_ms", ttl.Milliseconds()),
+	)
+	defer span.End()
+
+	c.cache.Set(key, value, ttl)
+}
+
+/*
+Delete wraps tempuscache.Cache.Delete in a span recording the key hash.
+*/
+
+func (c *Cache) Delete(ctx context.Context, key string) {
+	_, span := c.start(ctx, "tempuscache.Delete", attribute.String("cache.key_hash", c.hashKey(key)))
+	defer span.End()
+
+	c.cache.Delete(key)
+}
+
+/*
+GetMany wraps tempuscache.Cache.GetMany in a single span recording
+the batch size and number of hits.
+*/
+
+func (c *Cache) GetMany(ctx context.Context, keys []string) map[string]interface{} {
+	_, span := c.start(ctx, "tempuscache.GetMany", attribute.Int("cache.keys", len(keys)))
+	defer span.End()
+
+	result := c.cache.GetMany(keys)
+	span.SetAttributes(attribute.Int("cache.hits", len(result)))
+	return result
+}
+
+/*
+SetMany wraps tempuscache.Cache.SetMany in a single span recording
+the batch size.
+*/
+
+func (c *Cache) SetMany(ctx context.Context, entries []tempuscache.Entry) {
+	_, span := c.start(ctx, "tempuscache.SetMany", attribute.Int("cache.keys", len(entries)))
+	defer span.End()
+
+	c.cache.SetMany(entries)
+}
+
+func (c *Cache) start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return c.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(append(attrs, c.attrs...)...),
+	)
+}
+
+func (c *Cache) registerMetrics(meter metric.Meter) error {
+	hits, err := meter.Int64ObservableCounter("tempuscache.hits", metric.WithDescription("Total number of cache hits."))
+	if err != nil {
+		return err
+	}
+	misses, err := meter.Int64ObservableCounter("tempuscache.misses", metric.WithDescription("Total number of cache misses."))
+	if err != nil {
+		return err
+	}
+	sets, err := meter.Int64ObservableCounter("tempuscache.sets", metric.WithDescription("Total number of successful writes."))
+	if err != nil {
+		return err
+	}
+	deletes, err := meter.Int64ObservableCounter("tempuscache.deletes", metric.WithDescription("Total number of explicitly deleted entries."))
+	if err != nil {
+		return err
+	}
+	evictions, err := meter.Int64ObservableCounter("tempuscache.evictions", metric.WithDescription("Total number of entries evicted due to capacity limits."))
+	if err != nil {
+		return err
+	}
+	expirations, err := meter.Int64ObservableCounter("tempuscache.expirations", metric.WithDescription("Total number of entries removed after their TTL elapsed."))
+	if err != nil {
+		return err
+	}
+	entries, err := meter.Int64ObservableGauge("tempuscache.entries", metric.WithDescription("Current number of stored entries."))
+	if err != nil {
+		return err
+	}
+	cost, err := meter.Int64ObservableGauge("tempuscache.cost", metric.WithDescription("Current total cost of stored entries."))
+	if err != nil {
+		return err
+	}
+
+	attrs := metric.WithAttributes(c.attrs...)
+
+	c.registration, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		stats := c.cache.Stats()
+		o.ObserveInt64(hits, int64(stats.Hits), attrs)
+		o.ObserveInt64(misses, int64(stats.Misses), attrs)
+		o.ObserveInt64(sets, int64(stats.Sets), attrs)
+		o.ObserveInt64(deletes, int64(stats.Deletes), attrs)
+		o.ObserveInt64(evictions, int64(stats.Evictions), attrs)
+		o.ObserveInt64(expirations, int64(stats.Expirations), attrs)
+		o.ObserveInt64(entries, stats.Entries, attrs)
+		o.ObserveInt64(cost, stats.Cost, attrs)
+		return nil
+	}, hits, misses, sets, deletes, evictions, expirations, entries, cost)
+	return err
+}
+
+/*
+hashKey returns a short key fingerprint: the first 64 bits of
+HMAC-SHA256(secret, key), hex encoded.
+*/
+
+func (c *Cache) hashKey(key string) string {
+	h := hmac.New(sha256.New, c.keySecret)
+	h.Write([]byte(key))
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
@@ -0,0 +1,132 @@
+package otelcache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/Krishna8167/tempuscache/v2"
+)
+
+/*
+TestSpans verifies that wrapped operations emit spans with the
+expected names and attributes, using the in-memory span exporter.
+*/
+
+func TestSpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	c, err := New(tempuscache.New(tempuscache.WithName("users")), WithTracerProvider(tp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	ctx := context.Background()
+	c.Set(ctx, "a", 1, time.Second)
+	c.Get(ctx, "a")
+	c.Get(ctx, "b")
+
+	spans := exporter.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(spans))
+	}
+
+	if spans[0].Name != "tempuscache.Set" || !hasAttr(spans[0].Attributes, attribute.Int64("cache.ttl_ms", 1000)) {
+		t.Fatalf("unexpected Set span %+v", spans[0])
+	}
+	if !hasAttr(spans[1].Attributes, attribute.Bool("cache.hit", true)) {
+		t.Fatalf("expected hit attribute on first Get, got %v", spans[1].Attributes)
+	}
+	if !hasAttr(spans[2].Attributes, attribute.Bool("cache.hit", false)) {
+		t.Fatalf("expected miss attribute on second Get, got %v", spans[2].Attributes)
+	}
+	if !hasAttr(spans[2].Attributes, attribute.String("cache.name", "users")) {
+		t.Fatalf("expected cache name attribute, got %v", spans[2].Attributes)
+	}
+	if hasAttr(spans[1].Attributes, attribute.String("cache.key_hash", "a")) {
+		t.Fatal("expected key to be hashed")
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	c, err := New(tempuscache.New(), WithMeterProvider(mp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	ctx := context.Background()
+	c.Set(ctx, "a", 1, 0)
+	c.Get(ctx, "a")
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatal(err)
+	}
+
+	values := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				values[m.Name] = data.DataPoints[0].Value
+			case metricdata.Gauge[int64]:
+				values[m.Name] = data.DataPoints[0].Value
+			}
+		}
+	}
+
+	if values["tempuscache.hits"] != 1 || values["tempuscache.sets"] != 1 || values["tempuscache.entries"] != 1 {
+		t.Fatalf("unexpected metric values %v", values)
+	}
+}
+
+func hasAttr(attrs []attribute.KeyValue, want attribute.KeyValue) bool {
+	for _, a := range attrs {
+		if a == want {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+TestKeyHash verifies that key hashes are keyed: stable for one
+secret, different across secrets, and random by default.
+*/
+
+func TestKeyHash(t *testing.T) {
+	cache := tempuscache.New()
+
+	a, _ := New(cache, WithKeyHashSecret([]byte("secret-a")))
+	b, _ := New(cache, WithKeyHashSecret([]byte("secret-a")))
+	other, _ := New(cache, WithKeyHashSecret([]byte("secret-b")))
+	random, _ := New(cache)
+	for _, c := range []*Cache{a, b, other, random} {
+		defer c.Close()
+	}
+
+	if a.hashKey("user:1") != b.hashKey("user:1") {
+		t.Fatal("expected equal secrets to produce equal hashes")
+	}
+	if a.hashKey("user:1") == a.hashKey("user:2") {
+		t.Fatal("expected distinct keys to produce distinct hashes")
+	}
+	if a.hashKey("user:1") == other.hashKey("user:1") || a.hashKey("user:1") == random.hashKey("user:1") {
+		t.Fatal("expected hashes to depend on the secret")
+	}
+	if n := len(a.hashKey("user:1")); n != 16 {
+		t.Fatalf("expected a 64-bit hex fingerprint, got %d chars", n)
+	}
+}
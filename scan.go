@@ -0,0 +1,290 @@
+package tempuscache
+
+import "strings"
+
+/*
+Cursor-based key scanning and prefix deletion.
+
+================================================================================
+SCAN MODEL
+================================================================================
+
+Scan works like Redis SCAN MATCH ... COUNT ...:
+
+    cursor := ""
+    for {
+        keys, next := cache.Scan(cursor, "user:*", 100)
+        process(keys)
+        if next == "" {
+            break
+        }
+        cursor = next
+    }
+
+- The cursor is the last key examined; "" starts (and ends) a scan.
+- count bounds the WORK per call, not the result size: each call
+  examines up to count indexed keys in order (live or not) and
+  returns the live ones matching the pattern. A call may therefore return zero keys with a non-empty
+  cursor; keep iterating until the cursor is "".
+- Keys present for the whole scan are returned exactly once. Keys
+  added or removed concurrently may or may not be returned.
+- Returned keys are a snapshot; they may expire or be deleted before
+  the caller uses them.
+
+================================================================================
+LOCKING
+================================================================================
+
+Scan walks the ordered radix key index (see keyindex.go) and
+therefore REQUIRES WithKeyIndex. Each call holds the read lock only
+while examining `count` keys, so a full scan never blocks writers for
+longer than one batch and costs O(n) in total.
+
+Without the index Scan returns (nil, "") immediately: an unordered
+map offers no cursor to resume from, and re-copying the key set on
+every call would hold the lock for O(n) per batch. The index stays
+opt-in because it makes every insert and removal O(key length).
+
+================================================================================
+PATTERNS
+================================================================================
+
+Glob syntax (Redis-compatible):
+
+    *        any sequence of characters (including none)
+    ?        any single character
+    [abc]    one character from the set
+    [a-z]    one character from the range
+    [^a]     any character except those listed
+    \x       the literal character x
+
+The literal prefix of the pattern (everything before the first
+special character) is used to skip irrelevant parts of the index.
+*/
+
+/*
+DefaultScanCount is used when Scan is called with count <= 0.
+*/
+
+const DefaultScanCount = 10
+
+/*
+Scan returns keys matching pattern, resuming after cursor.
+See the file-level documentation for the full contract.
+
+Scan requires WithKeyIndex; without it, it returns (nil, "").
+
+An empty pattern matches every key.
+*/
+
+func (c *Cache) Scan(cursor string, pattern string, count int) ([]string, string) {
+	if count <= 0 {
+		count = DefaultScanCount
+	}
+	if pattern == "" {
+		pattern = "*"
+	}
+	prefix := literalPrefix(pattern)
+
+	var live []string
+	visited := 0
+
+	c.mu.RLock()
+	if c.index == nil {
+		c.mu.RUnlock()
+		return nil, ""
+	}
+	now := c.now()
+	c.index.walk(prefix, cursor, func(key string) bool {
+		// Hidden (expired, invalidated or negative) keys count as work
+		// too, so a mostly-dead prefix cannot stretch one call.
+		visited++
+		if item, found := c.store.get(key); found && !item.hidden(now) {
+			live = append(live, key)
+		}
+		cursor = key
+		return visited < count
+	})
+	// If fewer than count keys were visited the walk is complete.
+	if visited < count {
+		cursor = ""
+	}
+	c.mu.RUnlock()
+
+	keys := live[:0]
+	for _, key := range live {
+		if matchGlob(pattern, key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, cursor
+}
+
+/*
+DeleteByPrefix removes every key starting with prefix.
+
+RETURNS:
+The number of removed live entries — those Has would have reported.
+Their removals are published as EventDelete with ReasonDeleted and
+counted in Stats.Deletes. Matching entries that had already expired
+are reclaimed as lazy expirations; matching negative entries
+(SetNotFound) are deleted but not counted.
+
+TIME COMPLEXITY:
+- With WithKeyIndex : O(k) for k matching keys
+- Without           : O(n) scan of all keys
+*/
+
+func (c *Cache) DeleteByPrefix(prefix string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var keys []string
+	if c.index != nil {
+		c.index.walk(prefix, "", func(key string) bool {
+			keys = append(keys, key)
+			return true
+		})
+	} else {
+		c.store.forEach(func(item *Item) bool {
+			if strings.HasPrefix(item.key, prefix) {
+				keys = append(keys, item.key)
+			}
+			return true
+		})
+	}
+
+	now := c.now()
+	removed := 0
+	for _, key := range keys {
+		item, found := c.store.get(key)
+		if !found {
+			continue
+		}
+		if item.expiredAt(now) {
+			c.removeElement(item, ReasonExpiredLazy)
+			c.stats.lazyExpirations.Add(1)
+			continue
+		}
+		if !item.negative {
+			removed++
+		}
+		c.delete(key)
+	}
+	return removed
+}
+
+/*
+literalPrefix returns the part of a glob pattern before its first
+special character.
+*/
+
+func literalPrefix(pattern string) string {
+	if i := strings.IndexAny(pattern, `*?[\`); i >= 0 {
+		return pattern[:i]
+	}
+	return pattern
+}
+
+/*
+matchGlob reports whether name matches the glob pattern.
+
+'*' is handled by remembering the most recent star position and
+backtracking to it on mismatch, which keeps matching linear in
+practice and avoids recursion.
+*/
+
+func matchGlob(pattern, name string) bool {
+	p, n := 0, 0
+	starP, starN := -1, 0
+
+	for n < len(name) {
+		if p < len(pattern) {
+			switch pattern[p] {
+			case '*':
+				starP, starN = p, n
+				p++
+				continue
+			case '?':
+				p++
+				n++
+				continue
+			case '[':
+				if end, ok := matchClass(pattern[p:], name[n]); end > 0 {
+					if ok {
+						p += end
+						n++
+						continue
+					}
+				} else if pattern[p] == name[n] {
+					p++
+					n++
+					continue
+				}
+			case '\\':
+				if p+1 < len(pattern) && pattern[p+1] == name[n] {
+					p += 2
+					n++
+					continue
+				}
+			default:
+				if pattern[p] == name[n] {
+					p++
+					n++
+					continue
+				}
+			}
+		}
+
+		if starP < 0 {
+			return false
+		}
+		starN++
+		p, n = starP+1, starN
+	}
+
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
+
+/*
+matchClass evaluates a bracket expression at the start of pattern
+against b. It returns the length of the expression (0 if it is not
+terminated, in which case '[' is treated as a literal) and whether
+b matched.
+*/
+
+func matchClass(pattern string, b byte) (int, bool) {
+	i := 1
+	negate := false
+	if i < len(pattern) && pattern[i] == '^' {
+		negate = true
+		i++
+	}
+
+	matched := false
+	for first := true; i < len(pattern); first = false {
+		ch := pattern[i]
+		if ch == ']' && !first {
+			return i + 1, matched != negate
+		}
+		if ch == '\\' && i+1 < len(pattern) {
+			i++
+			ch = pattern[i]
+		}
+		if i+2 < len(pattern) && pattern[i+1] == '-' && pattern[i+2] != ']' {
+			if ch <= b && b <= pattern[i+2] {
+				matched = true
+			}
+			i += 3
+			continue
+		}
+		if ch == b {
+			matched = true
+		}
+		i++
+	}
+	return 0, false
+}
@@ -0,0 +1,194 @@
+package tempuscache
+
+import "hash/maphash"
+
+/*
+Pointer-free slab storage.
+
+================================================================================
+MOTIVATION
+================================================================================
+
+The default layout (map[string]*Item + an intrusive linked list)
+gives every entry its own heap-allocated *Item linked to its
+neighbours, and the map itself is full of pointers. The GC has to visit all of them on every cycle, so
+mark time grows with the number of entries.
+
+WithSlabStorage switches to a layout the GC can mostly skip:
+
+    index   map[uint64]uint32   key hash → slot (no pointers: not scanned)
+    entries []slabEntry         one allocation holding every Item
+
+LRU order is an intrusive doubly linked list of slot numbers stored
+inside the entries themselves, so there are no pointers between
+entries at all.
+Items still contain their key and value, so the entries slice is
+scanned, but as one contiguous object instead of millions of small
+ones.
+
+================================================================================
+LAYOUT
+================================================================================
+
+- Slot 0 is the LRU sentinel: entries[0].next is the most recently
+  used slot, entries[0].prev the least recently used. 0 doubles as
+  the "no slot" value everywhere.
+- Entries whose keys share a hash are chained through `chain`.
+  Lookups compare the full key, so collisions are always resolved.
+- Freed slots are zeroed (releasing key and value to the GC) and
+  kept on a free list, also linked through `chain`, for reuse by
+  the next insert.
+
+Growing the slice moves the entries, which is why *Item pointers are
+only valid until the next pushFront (see store.go).
+
+================================================================================
+TRADE-OFFS
+================================================================================
+
+- Lower GC mark cost; no per-entry heap objects at all.
+- The entries slice never shrinks below its high-water mark (until
+  Close); freed slots are reused instead.
+- Keys are hashed with hash/maphash on every lookup.
+*/
+
+type slabEntry struct {
+	item       Item
+	hash       uint64
+	prev, next uint32 // LRU neighbours (0 = sentinel)
+	chain      uint32 // next slot in the hash chain, or in the free list
+}
+
+type slabStore struct {
+	seed    maphash.Seed
+	index   map[uint64]uint32
+	entries []slabEntry
+	free    uint32 // first free slot, 0 if none
+	count   int
+}
+
+func newSlabStore() *slabStore {
+	return &slabStore{
+		seed:    maphash.MakeSeed(),
+		index:   make(map[uint64]uint32),
+		entries: make([]slabEntry, 1),
+	}
+}
+
+func (s *slabStore) get(key string) (*Item, bool) {
+	h := maphash.String(s.seed, key)
+	for slot := s.index[h]; slot != 0; slot = s.entries[slot].chain {
+		if s.entries[slot].item.key == key {
+			return &s.entries[slot].item, true
+		}
+	}
+	return nil, false
+}
+
+func (s *slabStore) pushFront(item Item) *Item {
+	slot := s.free
+	if slot != 0 {
+		s.free = s.entries[slot].chain
+	} else {
+		slot = uint32(len(s.entries))
+		s.entries = append(s.entries, slabEntry{})
+	}
+
+	h := maphash.String(s.seed, item.key)
+	e := &s.entries[slot]
+	e.item = item
+	e.item.slot = slot
+	e.hash = h
+	e.chain = s.index[h]
+	s.index[h] = slot
+
+	s.link(slot)
+	s.count++
+	return &e.item
+}
+
+func (s *slabStore) remove(item *Item) {
+	slot := item.slot
+	e := &s.entries[slot]
+	s.unlink(slot)
+
+	if head := s.index[e.hash]; head == slot {
+		if e.chain == 0 {
+			delete(s.index, e.hash)
+		} else {
+			s.index[e.hash] = e.chain
+		}
+	} else {
+		prev := head
+		for s.entries[prev].chain != slot {
+			prev = s.entries[prev].chain
+		}
+		s.entries[prev].chain = e.chain
+	}
+
+	*e = slabEntry{chain: s.free}
+	s.free = slot
+	s.count--
+}
+
+func (s *slabStore) moveToFront(item *Item) {
+	if s.entries[0].next == item.slot {
+		return
+	}
+	s.unlink(item.slot)
+	s.link(item.slot)
+}
+
+func (s *slabStore) back() *Item {
+	if slot := s.entries[0].prev; slot != 0 {
+		return &s.entries[slot].item
+	}
+	return nil
+}
+
+func (s *slabStore) forEach(fn func(*Item) bool) {
+	for slot := s.entries[0].next; slot != 0; slot = s.entries[slot].next {
+		if !fn(&s.entries[slot].item) {
+			return
+		}
+	}
+}
+
+func (s *slabStore) forEachBack(fn func(*Item) bool) {
+	for slot := s.entries[0].prev; slot != 0; {
+		prev := s.entries[slot].prev
+		if !fn(&s.entries[slot].item) {
+			return
+		}
+		slot = prev
+	}
+}
+
+func (s *slabStore) len() int {
+	return s.count
+}
+
+func (s *slabStore) reset() {
+	s.index = make(map[uint64]uint32)
+	s.entries = make([]slabEntry, 1)
+	s.free = 0
+	s.count = 0
+}
+
+/*
+link inserts slot right after the sentinel (most recently used).
+*/
+
+func (s *slabStore) link(slot uint32) {
+	first := s.entries[0].next
+	s.entries[slot].prev = 0
+	s.entries[slot].next = first
+	s.entries[first].prev = slot
+	s.entries[0].next = slot
+}
+
+func (s *slabStore) unlink(slot uint32) {
+	e := &s.entries[slot]
+	s.entries[e.prev].next = e.next
+	s.entries[e.next].prev = e.prev
+}
@@ -1,5 +1,7 @@
 package tempuscache
 
+import "sync/atomic"
+
 /*
 Stats represents runtime performance metrics of the cache.
 
@@ -9,9 +11,21 @@ PURPOSE
 
 This structure tracks key operational indicators:
 
-- Hits      → Successful retrievals (valid key found)
-- Misses    → Failed lookups (missing or expired key)
-- Evictions → Entries removed due to LRU capacity constraints
+- Hits         → Successful retrievals (valid key found)
+- Misses       → Failed lookups (missing or expired key)
+- Sets         → Successful writes (inserts and updates)
+- Replacements → Writes that overwrote an existing live key
+- Deletes      → Entries removed by explicit deletion
+- Expirations  → Entries removed because their TTL elapsed
+                 (LazyExpirations + JanitorExpirations)
+- Evictions    → Entries removed to enforce capacity
+                 (CapacityEvictions + ResizeEvictions)
+- Entries      → Current number of stored entries, including
+                 expired entries not yet reclaimed
+- Cost         → Current total cost of stored entries (see WithCost)
+- NegativeSets → Known-missing entries written by SetNotFound
+- NegativeHits → Lookups answered by a negative entry (counted in
+                 neither Hits nor Misses; see negative.go)
 
 These metrics provide visibility into cache effectiveness
 and operational behavior.
@@ -32,30 +46,159 @@ For example:
 
     hit_ratio = Hits / (Hits + Misses)
 
+which is available directly as Stats.HitRatio().
+
 ================================================================================
 CONCURRENCY MODEL
 ================================================================================
 
-Stats fields are modified under Cache-level locking.
-The Stats() method returns a snapshot under read lock,
-ensuring consistent reads without race conditions.
+Stats is a plain value snapshot. The live counters behind it are
+atomic (see counters), so Stats() never touches the cache mutex and
+monitoring cannot contend with request traffic.
+
+Each individual field is read atomically. Fields are NOT read as one
+transaction, so e.g. Hits and Misses may be a few operations apart
+under heavy load. ResetStats, which must be exact, excludes writers.
+*/
+
+type Stats struct {
+	Hits         uint64
+	Misses       uint64
+	Sets         uint64
+	Replacements uint64
+	Deletes      uint64
+
+	Expirations        uint64
+	LazyExpirations    uint64
+	JanitorExpirations uint64
+
+	Evictions         uint64
+	CapacityEvictions uint64
+	ResizeEvictions   uint64
+
+	Entries int64
+	Cost    int64
+
+	NegativeSets uint64
+	NegativeHits uint64
+}
+
+/*
+HitRatio returns Hits / (Hits + Misses), or 0 if no lookups
+have been recorded.
+*/
+
+func (s Stats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+/*
+counters holds the live, atomically updated statistics.
 
 ================================================================================
-DESIGN SIMPLICITY
+DESIGN
 ================================================================================
 
-The struct is intentionally minimal:
+- Event counters (hits, sets, ...) only ever grow, until reset.
+- Gauges (entries, cost) track current state and are never reset.
+
+Counters are incremented wherever the corresponding event happens,
+usually while the cache lock is already held for the mutation itself.
+The atomics exist so that READERS (Stats) do not need the lock.
+*/
+
+type counters struct {
+	hits         atomic.Uint64
+	misses       atomic.Uint64
+	sets         atomic.Uint64
+	replacements atomic.Uint64
+	deletes      atomic.Uint64
+
+	lazyExpirations    atomic.Uint64
+	janitorExpirations atomic.Uint64
+
+	capacityEvictions atomic.Uint64
+	resizeEvictions   atomic.Uint64
+
+	entries atomic.Int64
+	cost    atomic.Int64
+
+	negativeSets atomic.Uint64
+	negativeHits atomic.Uint64
+}
+
+func (s *counters) snapshot() Stats {
+	st := Stats{
+		Hits:               s.hits.Load(),
+		Misses:             s.misses.Load(),
+		Sets:               s.sets.Load(),
+		Replacements:       s.replacements.Load(),
+		Deletes:            s.deletes.Load(),
+		LazyExpirations:    s.lazyExpirations.Load(),
+		JanitorExpirations: s.janitorExpirations.Load(),
+		CapacityEvictions:  s.capacityEvictions.Load(),
+		ResizeEvictions:    s.resizeEvictions.Load(),
+		Entries:            s.entries.Load(),
+		Cost:               s.cost.Load(),
+		NegativeSets:       s.negativeSets.Load(),
+		NegativeHits:       s.negativeHits.Load(),
+	}
+	st.Expirations = st.LazyExpirations + st.JanitorExpirations
+	st.Evictions = st.CapacityEvictions + st.ResizeEvictions
+	return st
+}
+
+func (s *counters) reset() Stats {
+	st := Stats{
+		Hits:               s.hits.Swap(0),
+		Misses:             s.misses.Swap(0),
+		Sets:               s.sets.Swap(0),
+		Replacements:       s.replacements.Swap(0),
+		Deletes:            s.deletes.Swap(0),
+		LazyExpirations:    s.lazyExpirations.Swap(0),
+		JanitorExpirations: s.janitorExpirations.Swap(0),
+		CapacityEvictions:  s.capacityEvictions.Swap(0),
+		ResizeEvictions:    s.resizeEvictions.Swap(0),
+		Entries:            s.entries.Load(),
+		Cost:               s.cost.Load(),
+		NegativeSets:       s.negativeSets.Swap(0),
+		NegativeHits:       s.negativeHits.Swap(0),
+	}
+	st.Expirations = st.LazyExpirations + st.JanitorExpirations
+	st.Evictions = st.CapacityEvictions + st.ResizeEvictions
+	return st
+}
+
+/*
+Stats returns a snapshot of the cache statistics.
+
+This method is lock-free; see the Stats type for consistency notes.
+*/
+
+func (c *Cache) Stats() Stats {
+	return c.stats.snapshot()
+}
+
+/*
+ResetStats zeroes all event counters and returns their values
+immediately before the reset.
 
-- No internal locking
-- No atomic counters
-- Synchronization handled at Cache level
+The Entries and Cost gauges reflect current contents and are
+reported but not reset.
 
-This keeps the data structure lightweight
-and avoids unnecessary complexity.
+CONCURRENCY:
+The exclusive lock is held during the swap, so no operation can be
+counted in both the returned snapshot and the next period, and none
+can be lost in between.
 */
 
-type Stats struct {
-	Hits      uint64
-	Misses    uint64
-	Evictions uint64
+func (c *Cache) ResetStats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.stats.reset()
 }
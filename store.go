@@ -0,0 +1,186 @@
+package tempuscache
+
+/*
+Entry storage: key index plus LRU order.
+
+================================================================================
+ABSTRACTION
+================================================================================
+
+The cache needs exactly these operations from its storage:
+
+- get         → Find an entry by key (no side effects)
+- pushFront   → Insert a new entry as most recently used
+- remove      → Drop an entry
+- moveToFront → Mark an entry as most recently used
+- back        → The least recently used entry (eviction candidate)
+- forEach     → Visit entries from most to least recently used
+- forEachBack → Visit entries from least to most recently used; the
+                callback may remove the entry it is given
+- len / reset
+
+Everything above this interface (TTL, namespaces, tags, events,
+statistics, ...) works on *Item and is oblivious to the layout.
+
+================================================================================
+IMPLEMENTATIONS
+================================================================================
+
+listStore (default)
+    map[string]*Item plus an intrusive doubly linked LRU list. Every
+    entry is a separate heap object (recycled through a free list).
+
+slabStore (WithSlabStorage, see slab.go)
+    A pointer-free map[uint64]uint32 from key hash to a slot in one
+    slice of entries, with an intrusive, index-based LRU list.
+
+================================================================================
+POINTER VALIDITY
+================================================================================
+
+An *Item returned by a store stays valid until the entry is removed
+(both stores recycle removed items) or, for slabStore, until the next
+pushFront (which may grow the backing slice). Callers hold the cache
+lock and never keep an *Item across a removal or an insert.
+*/
+
+type store interface {
+	get(key string) (*Item, bool)
+	pushFront(item Item) *Item
+	remove(item *Item)
+	moveToFront(item *Item)
+	back() *Item
+	forEach(fn func(*Item) bool)
+	forEachBack(fn func(*Item) bool)
+	len() int
+	reset()
+}
+
+/*
+listStore is the default store: a hash map of items and an intrusive,
+circular doubly linked LRU list threaded through Item.prev/Item.next.
+
+================================================================================
+ITEM RECYCLING
+================================================================================
+
+Removed items are zeroed (releasing key and value to the GC) and kept
+on a free list, up to maxFreeItems, instead of being dropped. The next
+insert reuses one, so a cache running at capacity, where every insert
+evicts, allocates nothing for its entries in steady state.
+*/
+
+const maxFreeItems = 1024
+
+type listStore struct {
+	data  map[string]*Item
+	root  Item // sentinel: root.next is the front (MRU), root.prev the back (LRU)
+	count int
+	free  *Item // recycled items, linked through next
+	nfree int
+}
+
+func newListStore() *listStore {
+	s := &listStore{data: make(map[string]*Item)}
+	s.root.next = &s.root
+	s.root.prev = &s.root
+	return s
+}
+
+func (s *listStore) get(key string) (*Item, bool) {
+	item, found := s.data[key]
+	return item, found
+}
+
+func (s *listStore) pushFront(item Item) *Item {
+	it := s.free
+	if it != nil {
+		s.free = it.next
+		s.nfree--
+	} else {
+		it = new(Item)
+	}
+
+	*it = item
+	s.link(it)
+	s.data[it.key] = it
+	s.count++
+	return it
+}
+
+func (s *listStore) remove(item *Item) {
+	s.unlink(item)
+	delete(s.data, item.key)
+	s.count--
+
+	*item = Item{}
+	if s.nfree < maxFreeItems {
+		item.next = s.free
+		s.free = item
+		s.nfree++
+	}
+}
+
+func (s *listStore) moveToFront(item *Item) {
+	if s.root.next == item {
+		return
+	}
+	s.unlink(item)
+	s.link(item)
+}
+
+func (s *listStore) back() *Item {
+	if s.count == 0 {
+		return nil
+	}
+	return s.root.prev
+}
+
+func (s *listStore) forEach(fn func(*Item) bool) {
+	for item := s.root.next; item != &s.root; item = item.next {
+		if !fn(item) {
+			return
+		}
+	}
+}
+
+func (s *listStore) forEachBack(fn func(*Item) bool) {
+	for item := s.root.prev; item != &s.root; {
+		prev := item.prev
+		if !fn(item) {
+			return
+		}
+		item = prev
+	}
+}
+
+func (s *listStore) len() int {
+	return s.count
+}
+
+func (s *listStore) reset() {
+	s.data = make(map[string]*Item)
+	s.root.next = &s.root
+	s.root.prev = &s.root
+	s.count = 0
+	s.free = nil
+	s.nfree = 0
+}
+
+/*
+link inserts item right after the sentinel (most recently used).
+*/
+
+func (s *listStore) link(item *Item) {
+	item.prev = &s.root
+	item.next = s.root.next
+	s.root.next.prev = item
+	s.root.next = item
+}
+
+func (s *listStore) unlink(item *Item) {
+	item.prev.next = item.next
+	item.next.prev = item.prev
+	item.prev = nil
+	item.next = nil
+}
@@ -0,0 +1,127 @@
+package tempuscache
+
+import "time"
+
+/*
+Tag-based invalidation.
+
+================================================================================
+MOTIVATION
+================================================================================
+
+A single domain object often appears in many cache entries: a
+product shows up in its detail page, in category listings, in search
+results, in recommendations... When the product changes, every one
+of those entries must go, but their keys are unrelated.
+
+Tags attach that relationship at write time:
+
+    cache.SetWithTags("page:/p/42", html, ttl, "product:42")
+    cache.SetWithTags("list:shoes", list, ttl, "product:42", "product:7")
+
+    cache.InvalidateTag("product:42")   // drops both entries
+
+================================================================================
+INDEX
+================================================================================
+
+tags : map[tag] → set of keys
+
+- SetWithTags replaces the entry's tag set and updates the index.
+- Set, Update, counters, ... leave existing tags untouched.
+- Every removal (delete, expiration, eviction, invalidation) passes
+  through removeElement, which calls untag(); the index can therefore
+  never reference a key that is no longer stored.
+
+TIME COMPLEXITY:
+- SetWithTags   : O(t) for t tags
+- InvalidateTag : O(k) for k tagged keys
+*/
+
+/*
+SetWithTags inserts or updates key exactly like Set and associates
+it with the given tags, replacing any tags it had before.
+*/
+
+func (c *Cache) SetWithTags(key string, value interface{}, ttl time.Duration, tags ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return
+	}
+
+	item := c.set(key, value, ttl)
+	c.untag(item)
+
+	if len(tags) == 0 {
+		return
+	}
+
+	if c.tags == nil {
+		c.tags = make(map[string]map[string]struct{})
+	}
+
+	item.tags = append([]string(nil), tags...)
+	for _, tag := range item.tags {
+		keys, found := c.tags[tag]
+		if !found {
+			keys = make(map[string]struct{})
+			c.tags[tag] = keys
+		}
+		keys[key] = struct{}{}
+	}
+}
+
+/*
+InvalidateTag removes every entry carrying tag.
+
+RETURNS:
+The number of removed live entries. Their removals are published as
+EventDelete with ReasonInvalidated and counted in Stats.Deletes.
+Tagged entries that had already expired (or whose namespace was
+invalidated) are reclaimed as lazy expirations instead.
+*/
+
+func (c *Cache) InvalidateTag(tag string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	removed := 0
+	for key := range c.tags[tag] {
+		item, found := c.store.get(key)
+		if !found {
+			continue
+		}
+		if item.expiredAt(now) {
+			c.removeElement(item, ReasonExpiredLazy)
+			c.stats.lazyExpirations.Add(1)
+			continue
+		}
+		c.removeElement(item, ReasonInvalidated)
+		c.stats.deletes.Add(1)
+		removed++
+	}
+	delete(c.tags, tag)
+	return removed
+}
+
+/*
+untag removes item's key from every tag it belongs to and clears
+its tag list. Empty tag sets are deleted.
+
+NOTE:
+The caller must hold the exclusive lock.
+*/
+
+func (c *Cache) untag(item *Item) {
+	for _, tag := range item.tags {
+		keys := c.tags[tag]
+		delete(keys, item.key)
+		if len(keys) == 0 {
+			delete(c.tags, tag)
+		}
+	}
+	item.tags = nil
+}
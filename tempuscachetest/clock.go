@@ -0,0 +1,189 @@
+/*
+Package tempuscachetest provides test helpers for code using
+TempusCache.
+
+================================================================================
+FAKE CLOCK
+================================================================================
+
+Clock implements tempuscache.Clock with time that only moves when
+the test says so:
+
+	clock := tempuscachetest.NewClock(time.Unix(0, 0))
+	cache := tempuscache.New(
+	    tempuscache.WithClock(clock),
+	    tempuscache.WithCleanupInterval(time.Second),
+	)
+
+	cache.Set("a", 1, time.Minute)
+	clock.Advance(time.Minute + 1)   // "a" expired; janitor tick fired
+
+No test needs to sleep, so expiration tests are instant and
+deterministic.
+
+================================================================================
+TICK DELIVERY
+================================================================================
+
+Advance moves time forward and fires every ticker whose next tick
+is due. Like time.Ticker, multiple missed ticks are coalesced into
+one. Tick channels are unbuffered and Advance blocks until each due
+tick has been RECEIVED, so when Advance returns every janitor has
+at least started its sweep. To wait for the sweep itself to finish,
+combine with tempuscache.WithSweepHook.
+*/
+package tempuscachetest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Krishna8167/tempuscache/v2"
+)
+
+/*
+Clock is a manually driven tempuscache.Clock. It is safe for
+concurrent use.
+*/
+
+type Clock struct {
+	mu      sync.Mutex
+	now     time.Time
+	tickers []*Ticker
+}
+
+var _ tempuscache.Clock = (*Clock)(nil)
+
+/*
+NewClock returns a fake clock frozen at start.
+*/
+
+func NewClock(start time.Time) *Clock {
+	return &Clock{now: start}
+}
+
+/*
+Now returns the current fake time.
+*/
+
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+/*
+NewTicker creates a ticker that fires as fake time advances.
+*/
+
+func (c *Clock) NewTicker(d time.Duration) tempuscache.Ticker {
+	if d <= 0 {
+		panic("tempuscachetest: non-positive interval for NewTicker")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &Ticker{
+		clock:  c,
+		period: d,
+		next:   c.now.Add(d),
+		ch:     make(chan time.Time),
+		stop:   make(chan struct{}),
+	}
+	c.tickers = append(c.tickers, t)
+	return t
+}
+
+/*
+Advance moves the clock forward by d and delivers due ticks.
+*/
+
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	now := c.now
+
+	var due []*Ticker
+	for _, t := range c.tickers {
+		if !t.next.After(now) {
+			due = append(due, t)
+			for !t.next.After(now) {
+				t.next = t.next.Add(t.period)
+			}
+		}
+	}
+	c.mu.Unlock()
+
+	for _, t := range due {
+		select {
+		case t.ch <- now:
+		case <-t.stop:
+		}
+	}
+}
+
+/*
+Set moves the clock to t (which must not be earlier than Now)
+and delivers due ticks.
+*/
+
+func (c *Clock) Set(t time.Time) {
+	c.Advance(t.Sub(c.Now()))
+}
+
+/*
+Tickers returns the number of active (not stopped) tickers.
+Useful for asserting that a janitor has started or stopped.
+*/
+
+func (c *Clock) Tickers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.tickers)
+}
+
+/*
+Ticker is the fake ticker returned by Clock.NewTicker.
+*/
+
+type Ticker struct {
+	clock    *Clock
+	period   time.Duration
+	next     time.Time
+	ch       chan time.Time
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+/*
+C returns the tick channel.
+*/
+
+func (t *Ticker) C() <-chan time.Time {
+	return t.ch
+}
+
+/*
+Stop deregisters the ticker. Pending Advance calls blocked on this
+ticker are released.
+*/
+
+func (t *Ticker) Stop() {
+	t.stopOnce.Do(func() {
+		close(t.stop)
+
+		c := t.clock
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		for i, other := range c.tickers {
+			if other == t {
+				c.tickers = append(c.tickers[:i], c.tickers[i+1:]...)
+				break
+			}
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package tempuscachetest
+
+import (
+	"testing"
+	"time"
+)
+
+/*
+TestAdvanceDeliversTicks verifies that ticks fire only once their
+period has elapsed and that missed ticks are coalesced.
+*/
+
+func TestAdvanceDeliversTicks(t *testing.T) {
+	start := time.Unix(0, 0)
+	clock := NewClock(start)
+	ticker := clock.NewTicker(time.Second)
+
+	ticks := make(chan time.Time, 10)
+	go func() {
+		for tick := range ticker.C() {
+			ticks <- tick
+		}
+	}()
+
+	clock.Advance(999 * time.Millisecond)
+	if len(ticks) != 0 {
+		t.Fatal("expected no tick before the period elapsed")
+	}
+
+	clock.Advance(time.Millisecond)
+	if tick := <-ticks; !tick.Equal(start.Add(time.Second)) {
+		t.Fatalf("expected tick at 1s, got %v", tick)
+	}
+
+	clock.Advance(5 * time.Second)
+	if tick := <-ticks; !tick.Equal(start.Add(6 * time.Second)) {
+		t.Fatalf("expected coalesced tick at 6s, got %v", tick)
+	}
+	if len(ticks) != 0 {
+		t.Fatal("expected missed ticks to be coalesced")
+	}
+
+	if !clock.Now().Equal(start.Add(6 * time.Second)) {
+		t.Fatalf("unexpected Now %v", clock.Now())
+	}
+}
+
+/*
+TestStopReleasesTicker verifies that stopped tickers are deregistered
+and never block Advance.
+*/
+
+func TestStopReleasesTicker(t *testing.T) {
+	clock := NewClock(time.Unix(0, 0))
+	ticker := clock.NewTicker(time.Second)
+
+	if n := clock.Tickers(); n != 1 {
+		t.Fatalf("expected 1 ticker, got %d", n)
+	}
+
+	ticker.Stop()
+	ticker.Stop() // idempotent
+
+	if n := clock.Tickers(); n != 0 {
+		t.Fatalf("expected 0 tickers, got %d", n)
+	}
+
+	// Nobody receives from the stopped ticker; this must not block.
+	clock.Set(clock.Now().Add(time.Hour))
+}
@@ -0,0 +1,174 @@
+package tempuscache
+
+import "time"
+
+/*
+TTL introspection and manipulation.
+
+================================================================================
+MOTIVATION
+================================================================================
+
+Set is the only way to change an entry's lifetime, and it always
+rewrites the value. Worse, Set(key, value, 0) on an existing key
+KEEPS the old expiration, so there is no way to make a key permanent
+again without deleting it first.
+
+The methods in this file operate exclusively on Item.expiration:
+
+- TTL      → Inspect remaining lifetime
+- Expire   → Set a relative lifetime
+- ExpireAt → Set an absolute deadline
+- Persist  → Remove the expiration entirely
+- Touch    → Re-arm the last applied TTL (sliding expiration)
+
+None of them alter the stored value, its CAS version, or hit/miss
+statistics.
+
+================================================================================
+INTERACTION WITH LAZY EXPIRATION
+================================================================================
+
+All methods first check the entry through lookup():
+
+- An already expired entry is removed on the spot and reported as
+  missing. It can NOT be revived by Expire, Persist or Touch.
+- A deadline that is already in the past (Expire with d <= 0, or
+  ExpireAt with a past time) deletes the entry immediately, the same
+  way Redis EXPIRE does with a non-positive timeout.
+*/
+
+/*
+TTL returns the remaining lifetime of key.
+
+RETURNS:
+- (remaining, true) -> Key exists and expires after `remaining`
+- (0, true)         -> Key exists and never expires
+- (0, false)        -> Key does not exist or has expired
+
+An expiring key always reports at least 1ns so it can be
+distinguished from a persistent one.
+*/
+
+func (c *Cache) TTL(key string) (time.Duration, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, found := c.lookupValue(key)
+	if !found {
+		return 0, false
+	}
+
+	if item.expiration == 0 {
+		return 0, true
+	}
+
+	remaining := time.Duration(item.expiration - c.now())
+	if remaining < 1 {
+		remaining = 1
+	}
+	return remaining, true
+}
+
+/*
+Expire sets key to expire after d, measured from now.
+
+RETURNS:
+true if the key existed. If d <= 0 the key is deleted immediately.
+*/
+
+func (c *Cache) Expire(key string, d time.Duration) bool {
+	return c.ExpireAt(key, c.clock.Now().Add(d))
+}
+
+/*
+ExpireAt sets key to expire at the absolute time t.
+
+RETURNS:
+true if the key existed. If t is not in the future the key is
+deleted immediately.
+
+The TTL re-armed by Touch becomes the distance between now and t.
+Deadlines beyond WithMaxTTL are pulled in to the maximum.
+*/
+
+func (c *Cache) ExpireAt(key string, t time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, found := c.lookupValue(key)
+	if !found {
+		return false
+	}
+
+	now := c.clock.Now()
+	ttl := t.Sub(now)
+	if ttl <= 0 {
+		c.removeElement(item, ReasonDeleted)
+		c.stats.deletes.Add(1)
+		return true
+	}
+	if c.maxTTL > 0 && ttl > c.maxTTL {
+		ttl = c.maxTTL
+		t = now.Add(ttl)
+	}
+
+	item.expiration = t.UnixNano()
+	item.ttl = ttl
+	return true
+}
+
+/*
+Persist removes the expiration from key, making it permanent.
+With WithMaxTTL the maximum TTL is re-armed instead.
+
+RETURNS:
+true if the key existed (whether or not it had an expiration).
+*/
+
+func (c *Cache) Persist(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, found := c.lookupValue(key)
+	if !found {
+		return false
+	}
+
+	if c.maxTTL > 0 {
+		item.expiration = c.now() + int64(c.maxTTL)
+		item.ttl = c.maxTTL
+		return true
+	}
+	item.expiration = 0
+	item.ttl = 0
+	return true
+}
+
+/*
+Touch refreshes key's expiration using its most recently applied TTL
+and marks it as recently used.
+
+This implements sliding expiration: a session cached with a 30 minute
+TTL stays alive as long as it is touched at least every 30 minutes.
+
+RETURNS:
+true if the key existed. Keys without a TTL are only promoted in the
+LRU list.
+*/
+
+func (c *Cache) Touch(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, found := c.lookupValue(key)
+	if !found {
+		return false
+	}
+
+	if item.ttl > 0 {
+		item.expiration = c.now() + int64(item.ttl)
+	}
+	c.store.moveToFront(item)
+	return true
+}
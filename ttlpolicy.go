@@ -0,0 +1,87 @@
+package tempuscache
+
+import "time"
+
+/*
+Default and maximum TTL policy.
+
+================================================================================
+MOTIVATION
+================================================================================
+
+Every write takes a TTL, and ttl == 0 means "never expire". A caller
+that forgets the TTL therefore pins the entry in memory for the life
+of the process. Two options turn this into a cache-wide policy:
+
+    cache := tempuscache.New(
+        tempuscache.WithDefaultTTL(10*time.Minute),
+        tempuscache.WithMaxTTL(time.Hour),
+    )
+
+    cache.Set("a", v, tempuscache.DefaultExpiration) // 10 minutes
+    cache.Set("b", v, 24*time.Hour)                  // clamped to 1 hour
+    cache.Set("c", v, tempuscache.NoExpiration)      // clamped to 1 hour
+
+================================================================================
+SENTINELS
+================================================================================
+
+DefaultExpiration (0)
+    With WithDefaultTTL: use the default TTL, for new AND existing
+    keys (an update re-arms the entry with the default).
+    Without it: the historical behavior — a new entry never expires
+    and an update keeps the current expiration.
+
+NoExpiration (-1)
+    The entry never expires. On an update any existing expiration is
+    removed. With WithMaxTTL the entry expires after the maximum TTL
+    instead.
+
+================================================================================
+MAXIMUM TTL
+================================================================================
+
+WithMaxTTL bounds how long any entry can live, no matter which path
+set its lifetime:
+
+- Positive TTLs above the maximum are clamped, on inserts and updates.
+- Entries that would never expire get the maximum TTL.
+- Expire and ExpireAt deadlines beyond the maximum are pulled in, and
+  Persist re-arms the maximum instead of removing the expiration.
+
+Namespace default TTLs are applied before the cache policy, so a
+Namespace default takes precedence over WithDefaultTTL and is still
+clamped by WithMaxTTL.
+
+Counters keep their original expiration on increment, as before;
+the policy only applies when a counter is created.
+*/
+
+const (
+	// DefaultExpiration applies the cache default TTL (see WithDefaultTTL).
+	DefaultExpiration time.Duration = 0
+
+	// NoExpiration stores an entry that never expires (subject to
+	// WithMaxTTL).
+	NoExpiration time.Duration = -1
+)
+
+/*
+resolveTTL maps a caller supplied TTL to the TTL actually applied,
+according to the default and maximum TTL policy. The result is
+suitable for setExact.
+*/
+
+func (c *Cache) resolveTTL(ttl time.Duration) time.Duration {
+	switch {
+	case ttl == DefaultExpiration && c.defaultTTL > 0:
+		ttl = c.defaultTTL
+	case ttl == NoExpiration && c.maxTTL > 0:
+		return c.maxTTL
+	}
+
+	if c.maxTTL > 0 && ttl > c.maxTTL {
+		return c.maxTTL
+	}
+	return ttl
+}